@@ -1,22 +1,75 @@
 package create
 
 import (
+	"fmt"
+	"maps"
+	"os"
+
+	"github.com/geekswamp/zen/cmd/genz/internal/edit"
 	"github.com/geekswamp/zen/cmd/genz/internal/template"
 	"github.com/spf13/cobra"
 )
 
 var (
-	dir string
-	tm  = new(template.Make)
+	dir         string
+	dryRun      bool
+	templateDir string
+	vars        map[string]string
+	tm          = new(template.Make)
 )
 
 var CreateCmd = &cobra.Command{
-	Use:       "create",
-	Short:     "Create a new handler, repository, route, service or model.",
-	Args:      cobra.ExactArgs(1),
-	ValidArgs: []string{"handler", "repo", "route", "model", "service"},
+	Use:   "create",
+	Short: "Create a new field, handler, repository, resource, route, service or model.",
+	Long: "Create a new field, handler, repository, resource, route, service or model.\n\n" +
+		"A template in " + template.DefaultDir + " or --template-dir replaces the embedded template of the same name, " +
+		"e.g. model.tmpl. Custom kinds of files, the template directory and variables are declared in " + template.ConfigFile + ". " +
+		"Templates read variables as {{ .Vars.name }} and can use the snake, kebab, plural and singular functions.",
+	Args:              cobra.ExactArgs(1),
+	ValidArgs:         []string{"field", "handler", "repo", "resource", "route", "model", "service"},
+	PersistentPreRunE: runCreatePreE,
 }
 
 func init() {
-	CreateCmd.AddCommand(modelCmd, repoCmd, serviceCmd)
+	CreateCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Print the changes as a unified diff instead of writing them.")
+	CreateCmd.PersistentFlags().StringVar(&templateDir, "template-dir", "", "Specify the directory of templates overriding the embedded ones.")
+	CreateCmd.PersistentFlags().StringToStringVar(&vars, "var", nil, "Set a template variable as name=value, overriding "+template.ConfigFile+".")
+	CreateCmd.AddCommand(fieldCmd, handlerCmd, modelCmd, repoCmd, resourceCmd, routeCmd, serviceCmd)
+}
+
+func runCreatePreE(cmd *cobra.Command, _ []string) error {
+	if cmd.Flags().Changed("template-dir") {
+		if _, err := os.Stat(templateDir); err != nil {
+			return fmt.Errorf("template directory %s not found", templateDir)
+		}
+		template.Dir = templateDir
+	}
+
+	maps.Copy(template.Vars, vars)
+
+	return nil
+}
+
+// apply writes the changes, or prints them as a unified diff with --dry-run.
+func apply(cmd *cobra.Command, changes ...edit.Change) error {
+	if dryRun {
+		return edit.Diff(cmd.OutOrStdout(), changes...)
+	}
+
+	if err := edit.Apply(changes...); err != nil {
+		return err
+	}
+
+	for _, c := range changes {
+		switch {
+		case c.Old == nil:
+			fmt.Fprintln(cmd.OutOrStdout(), "Created", c.Path)
+		case c.Changed():
+			fmt.Fprintln(cmd.OutOrStdout(), "Updated", c.Path)
+		default:
+			fmt.Fprintln(cmd.OutOrStdout(), "Unchanged", c.Path)
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,62 @@
+package create
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/geekswamp/zen/cmd/genz/internal/template"
+	"github.com/spf13/cobra"
+)
+
+// AddGenerators adds a create command for each custom kind of file declared in the config.
+func AddGenerators(generators map[string]template.Generator) error {
+	for _, kind := range slices.Sorted(maps.Keys(generators)) {
+		if slices.ContainsFunc(CreateCmd.Commands(), func(c *cobra.Command) bool { return c.Name() == kind }) {
+			return fmt.Errorf("generator %s in %s clashes with genz create %s", kind, template.ConfigFile, kind)
+		}
+
+		CreateCmd.AddCommand(generatorCmd(kind, generators[kind]))
+		CreateCmd.ValidArgs = append(CreateCmd.ValidArgs, kind)
+	}
+
+	return nil
+}
+
+func generatorCmd(kind string, g template.Generator) *cobra.Command {
+	short := g.Short
+	if short == "" {
+		short = fmt.Sprintf("Create a new %s from the %s template.", kind, g.Template)
+	}
+
+	cmd := &cobra.Command{
+		Use:     kind + " <name>",
+		Short:   short,
+		Args:    cobra.ExactArgs(1),
+		Example: fmt.Sprintf("genz create %s order", kind),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			m := g.Make(args[0])
+			if dir != "" {
+				m.FilePath = template.FilePath(dir)
+			}
+
+			parsed, err := template.ParseFields(fields)
+			if err != nil {
+				return err
+			}
+			m.Fields = parsed
+
+			change, err := m.Change()
+			if err != nil {
+				return err
+			}
+
+			return apply(cmd, change)
+		},
+	}
+
+	cmd.Flags().StringVarP(&dir, "dir", "d", "", "Specify the directory, "+g.Dir+" by default.")
+	cmd.Flags().StringVarP(&fields, "fields", "f", "", "Specify fields as name:type pairs, available to the template as .Fields.")
+
+	return cmd
+}
@@ -0,0 +1,97 @@
+package create
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/geekswamp/zen/cmd/genz/internal/edit"
+	"github.com/geekswamp/zen/cmd/genz/internal/format"
+	"github.com/geekswamp/zen/cmd/genz/internal/template"
+	"github.com/jinzhu/inflection"
+	"github.com/spf13/cobra"
+)
+
+var fieldCmd = &cobra.Command{
+	Use:   "field <model>",
+	Short: "Add fields to an existing model.",
+	Long: "Add fields to the struct of an existing model and a migration adding their columns. " +
+		"Fields the model already has are skipped. Request and response types of its handler are left as they are.",
+	Args:    cobra.ExactArgs(1),
+	Example: `genz create field product --fields "stock:int,sku:string"`,
+	RunE:    runFieldE,
+}
+
+func init() {
+	fieldCmd.Flags().StringVarP(&dir, "dir", "d", "", "Specify the model directory.")
+	fieldCmd.Flags().StringVarP(&fields, "fields", "f", "", "Specify the fields to add as name:type pairs.")
+	_ = fieldCmd.MarkFlagRequired("fields")
+}
+
+func runFieldE(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	parsed, err := template.ParseFields(fields)
+	if err != nil {
+		return err
+	}
+
+	tm.FeatureName = name
+	tm.FileType = template.Fields
+
+	if dir != "" {
+		tm.FilePath = template.FilePath(dir)
+	} else {
+		tm.FilePath = template.ModelPath
+	}
+
+	f, err := edit.Open(tm.Path())
+	if err != nil {
+		return err
+	}
+
+	existing, err := f.FieldNames(format.ToPascalCase(name))
+	if err != nil {
+		return err
+	}
+
+	// Only the missing fields get a migration, so that rolling it back keeps the existing columns.
+	parsed = slices.DeleteFunc(parsed, func(field template.Field) bool { return slices.Contains(existing, field.GoName()) })
+	if len(parsed) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "The model already has every field")
+		return nil
+	}
+
+	tm.Fields = parsed
+	src, err := tm.Render()
+	if err != nil {
+		return err
+	}
+
+	if err := f.AddFields(format.ToPascalCase(name), string(src)); err != nil {
+		return err
+	}
+
+	if template.HasUUID(parsed) {
+		if err := f.AddImport("github.com/google/uuid"); err != nil {
+			return err
+		}
+	}
+
+	version, err := template.NextMigration(string(template.MigrationPath))
+	if err != nil {
+		return err
+	}
+
+	migration := version + "_add_" + inflection.Plural(format.ToSnakeCase(name)) + "_fields"
+	up, err := template.Make{FilePath: template.MigrationPath, FileType: template.AddUp, FileName: migration + ".up", Ext: ".sql", FeatureName: name, Fields: parsed}.Change()
+	if err != nil {
+		return err
+	}
+
+	down, err := template.Make{FilePath: template.MigrationPath, FileType: template.AddDown, FileName: migration + ".down", Ext: ".sql", FeatureName: name, Fields: parsed}.Change()
+	if err != nil {
+		return err
+	}
+
+	return apply(cmd, f.Change(), up, down)
+}
@@ -0,0 +1,48 @@
+package create
+
+import (
+	"path/filepath"
+
+	"github.com/geekswamp/zen/cmd/genz/internal/format"
+	"github.com/geekswamp/zen/cmd/genz/internal/template"
+	"github.com/spf13/cobra"
+)
+
+var handlerCmd = &cobra.Command{
+	Use:     "handler <name>",
+	Short:   "Create a new handler package with its request and response types.",
+	Args:    cobra.MinimumNArgs(1),
+	Example: "genz create handler user",
+	RunE:    runHandlerE,
+}
+
+func init() {
+	handlerCmd.Flags().StringVarP(&dir, "dir", "d", "", "Specify the handler directory.")
+}
+
+func runHandlerE(cmd *cobra.Command, args []string) error {
+	tm.FeatureName = args[0]
+
+	base := template.HandlerPath
+	if dir != "" {
+		base = template.FilePath(dir)
+	}
+	tm.FilePath = template.FilePath(filepath.Join(string(base), format.ToPackageName(args[0])))
+
+	tm.FileType = template.Types
+	tm.FileName = template.TypesFile
+	types, err := tm.Change()
+	if err != nil {
+		return err
+	}
+
+	tm.FileType = template.Handler
+	tm.FileName = ""
+	tm.SuffixFile = template.HandlerSuffix
+	handler, err := tm.Change()
+	if err != nil {
+		return err
+	}
+
+	return apply(cmd, types, handler)
+}
@@ -17,7 +17,7 @@ func init() {
 	modelCmd.Flags().StringVarP(&dir, "dir", "d", "", "Specify the model directory.")
 }
 
-func runModelE(_ *cobra.Command, args []string) error {
+func runModelE(cmd *cobra.Command, args []string) error {
 	tm.FeatureName = args[0]
 	tm.FileType = template.Model
 
@@ -27,9 +27,10 @@ func runModelE(_ *cobra.Command, args []string) error {
 		tm.FilePath = template.ModelPath
 	}
 
-	if err := tm.Generate(); err != nil {
+	change, err := tm.Change()
+	if err != nil {
 		return err
 	}
 
-	return nil
+	return apply(cmd, change)
 }
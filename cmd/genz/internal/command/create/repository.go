@@ -17,7 +17,7 @@ func init() {
 	repoCmd.Flags().StringVarP(&dir, "dir", "d", "", "Specify the repository directory.")
 }
 
-func runRepoE(_ *cobra.Command, args []string) error {
+func runRepoE(cmd *cobra.Command, args []string) error {
 	tm.FeatureName = args[0]
 	tm.FileType = template.Repository
 	tm.SuffixFile = template.RepoSuffix
@@ -28,9 +28,10 @@ func runRepoE(_ *cobra.Command, args []string) error {
 		tm.FilePath = template.RepositoryPath
 	}
 
-	if err := tm.Generate(); err != nil {
+	change, err := tm.Change()
+	if err != nil {
 		return err
 	}
 
-	return nil
+	return apply(cmd, change)
 }
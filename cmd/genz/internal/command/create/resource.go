@@ -0,0 +1,203 @@
+package create
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/geekswamp/zen/cmd/genz/internal/edit"
+	"github.com/geekswamp/zen/cmd/genz/internal/format"
+	"github.com/geekswamp/zen/cmd/genz/internal/mod"
+	"github.com/geekswamp/zen/cmd/genz/internal/template"
+	"github.com/jinzhu/inflection"
+	"github.com/spf13/cobra"
+)
+
+const _WireImport = "github.com/google/wire"
+
+var (
+	fields   string
+	skipWire bool
+)
+
+var resourceCmd = &cobra.Command{
+	Use:   "resource <name>",
+	Short: "Create a model, repository, service, handler, routes, migration and DI wiring at once.",
+	Long: "Create a complete resource: a model with its migration, a repository, a service, a handler with " +
+		"request and response types and tests, the provider sets and injector in the di package and the " +
+		"routes in router.RegisterRouter. Wire is run afterwards to update wire_gen.go, unless --dry-run is given.\n\n" +
+		"Fields are given as name:type pairs, the supported types are string, text, int, int32, int64, float64, bool and uuid.",
+	Args:    cobra.ExactArgs(1),
+	Example: `genz create resource product --fields "title:string,price:int64,owner_id:uuid"`,
+	RunE:    runResourceE,
+}
+
+func init() {
+	resourceCmd.Flags().StringVarP(&fields, "fields", "f", "", "Specify the fields of the resource as name:type pairs.")
+	resourceCmd.Flags().BoolVar(&skipWire, "skip-wire", false, "Do not run Wire after generating the files.")
+}
+
+func runResourceE(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	parsed, err := template.ParseFields(fields)
+	if err != nil {
+		return err
+	}
+
+	version, err := template.NextMigration(string(template.MigrationPath))
+	if err != nil {
+		return err
+	}
+
+	var changes []edit.Change
+	for _, m := range resourceMakes(name, parsed, version) {
+		change, err := m.Change()
+		if err != nil {
+			return err
+		}
+		changes = append(changes, change)
+	}
+
+	route, err := routeChange(template.Make{FilePath: template.RouterPath, FeatureName: name})
+	if err != nil {
+		return err
+	}
+
+	wiring, err := diChanges(name)
+	if err != nil {
+		return err
+	}
+
+	if err := apply(cmd, append(append(changes, route), wiring...)...); err != nil {
+		return err
+	}
+
+	if dryRun || skipWire {
+		return nil
+	}
+
+	if err := runWire(string(template.DIPath)); err != nil {
+		return fmt.Errorf("failed to run wire, run it in %s once fixed: %v", template.DIPath, err)
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), "Updated", filepath.Join(string(template.DIPath), "wire_gen.go"))
+
+	return nil
+}
+
+// resourceMakes returns the files generated for a resource.
+func resourceMakes(name string, fields []template.Field, version string) []template.Make {
+	handlerPath := template.FilePath(filepath.Join(string(template.HandlerPath), format.ToPackageName(name)))
+	migration := version + "_create_" + inflection.Plural(format.ToSnakeCase(name))
+
+	makes := []template.Make{
+		{FilePath: template.ModelPath, FileType: template.Model},
+		{FilePath: template.RepositoryPath, FileType: template.Repository, SuffixFile: template.RepoSuffix},
+		{FilePath: template.ServicePath, FileType: template.Service, SuffixFile: template.ServiceSuffix},
+		{FilePath: handlerPath, FileType: template.Types, FileName: template.TypesFile},
+		{FilePath: handlerPath, FileType: template.Handler, SuffixFile: template.HandlerSuffix},
+		{FilePath: handlerPath, FileType: template.Test, SuffixFile: template.TestSuffix},
+		{FilePath: template.MigrationPath, FileType: template.Up, FileName: migration + ".up", Ext: ".sql"},
+		{FilePath: template.MigrationPath, FileType: template.Down, FileName: migration + ".down", Ext: ".sql"},
+	}
+
+	for i := range makes {
+		makes[i].FeatureName = name
+		makes[i].Fields = fields
+	}
+
+	return makes
+}
+
+// diChanges declares the provider sets of the resource in the di package and the injector
+// of its handler in wire.go.
+func diChanges(name string) ([]edit.Change, error) {
+	module, err := mod.GetModuleName()
+	if err != nil {
+		return nil, err
+	}
+
+	pascal := format.ToPascalCase(name)
+	pkg := format.ToPackageName(name)
+	handlerImport := *module + "/internal/handler/v1/" + pkg
+
+	injector, err := template.Make{FileType: template.Injector, FeatureName: name}.Render()
+	if err != nil {
+		return nil, err
+	}
+
+	edits := map[string][]func(f *edit.File) error{
+		template.RepositoryFile: {
+			func(f *edit.File) error { return f.AddImport(*module + "/internal/base") },
+			func(f *edit.File) error { return f.AddImport(*module + "/internal/repository") },
+			func(f *edit.File) error { return f.AddImport(_WireImport) },
+			func(f *edit.File) error {
+				return f.AddProviderSet(pascal+"RepositorySet", "PostgresSet", "base.NewRepo", "repository.New"+pascal+"Repo")
+			},
+		},
+		template.ServiceFile: {
+			func(f *edit.File) error { return f.AddImport(*module + "/internal/service") },
+			func(f *edit.File) error { return f.AddImport(_WireImport) },
+			func(f *edit.File) error { return f.AddProviderSet(pascal+"ServiceSet", "service.New"+pascal+"Service") },
+		},
+		template.HandlerFile: {
+			func(f *edit.File) error { return f.AddImport(*module + "/internal/http") },
+			func(f *edit.File) error { return f.AddImport(handlerImport) },
+			func(f *edit.File) error { return f.AddImport(_WireImport) },
+			func(f *edit.File) error { return f.AddProviderSet(pascal+"HandlerSet", "http.New", pkg+".New") },
+		},
+		template.WireFile: {
+			func(f *edit.File) error { return f.AddImport(handlerImport) },
+			func(f *edit.File) error { return f.AddImport(_WireImport) },
+			func(f *edit.File) error { return f.AddDecl(string(injector)) },
+		},
+	}
+
+	var changes []edit.Change
+	for _, file := range []string{template.RepositoryFile, template.ServiceFile, template.HandlerFile, template.WireFile} {
+		change, err := editFile(filepath.Join(string(template.DIPath), file), edits[file]...)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+// editFile applies the edits to the Go file at path and returns the resulting change.
+func editFile(path string, edits ...func(f *edit.File) error) (edit.Change, error) {
+	f, err := edit.Open(path)
+	if err != nil {
+		return edit.Change{}, err
+	}
+
+	for _, e := range edits {
+		if err := e(f); err != nil {
+			return edit.Change{}, err
+		}
+	}
+
+	return f.Change(), nil
+}
+
+// runWire runs the wire command in the directory, or the Wire version of the module when
+// it is not installed.
+func runWire(dir string) error {
+	var wire *exec.Cmd
+	if _, err := exec.LookPath("wire"); err == nil {
+		wire = exec.Command("wire")
+	} else if errors.Is(err, exec.ErrNotFound) {
+		wire = exec.Command("go", "run", "github.com/google/wire/cmd/wire")
+	} else {
+		return err
+	}
+
+	wire.Dir = dir
+	wire.Stdout = os.Stdout
+	wire.Stderr = os.Stderr
+
+	return wire.Run()
+}
@@ -0,0 +1,72 @@
+package create
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/geekswamp/zen/cmd/genz/internal/edit"
+	"github.com/geekswamp/zen/cmd/genz/internal/format"
+	"github.com/geekswamp/zen/cmd/genz/internal/template"
+	"github.com/spf13/cobra"
+)
+
+var routeCmd = &cobra.Command{
+	Use:   "route <name>",
+	Short: "Register the routes of a handler in the router.",
+	Long: "Register the CRUD routes of a handler created with genz create handler in router.RegisterRouter. " +
+		"The handler is built with di.Init<Name>Handler, which must be declared in the di package.",
+	Args:    cobra.MinimumNArgs(1),
+	Example: "genz create route user",
+	RunE:    runRouteE,
+}
+
+func init() {
+	routeCmd.Flags().StringVarP(&dir, "dir", "d", "", "Specify the router directory.")
+}
+
+func runRouteE(cmd *cobra.Command, args []string) error {
+	tm.FeatureName = args[0]
+
+	if dir != "" {
+		tm.FilePath = template.FilePath(dir)
+	} else {
+		tm.FilePath = template.RouterPath
+	}
+
+	change, err := routeChange(*tm)
+	if err != nil {
+		return err
+	}
+
+	if err := apply(cmd, change); err != nil {
+		return err
+	}
+
+	if !dryRun && change.Changed() {
+		fmt.Fprintf(cmd.OutOrStdout(), "Registered routes under /api/v1/%s, declare di.Init%sHandler if it does not exist yet.\n",
+			format.ToKebabCase(args[0]), format.ToPascalCase(args[0]))
+	}
+
+	return nil
+}
+
+// routeChange adds the routes of the feature to router.RegisterRouter in the router directory of m.
+func routeChange(m template.Make) (edit.Change, error) {
+	m.FileType = template.Router
+
+	src, err := m.Render()
+	if err != nil {
+		return edit.Change{}, err
+	}
+
+	f, err := edit.Open(filepath.Join(string(m.FilePath), template.RouterFile))
+	if err != nil {
+		return edit.Change{}, err
+	}
+
+	if err := f.AddStmts(template.RouterFunc, string(src)); err != nil {
+		return edit.Change{}, err
+	}
+
+	return f.Change(), nil
+}
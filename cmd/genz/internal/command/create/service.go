@@ -17,7 +17,7 @@ func init() {
 	serviceCmd.Flags().StringVarP(&dir, "dir", "d", "", "Specify the service directory.")
 }
 
-func runServiceE(_ *cobra.Command, args []string) error {
+func runServiceE(cmd *cobra.Command, args []string) error {
 	tm.FeatureName = args[0]
 	tm.FileType = template.Service
 	tm.SuffixFile = template.ServiceSuffix
@@ -28,9 +28,10 @@ func runServiceE(_ *cobra.Command, args []string) error {
 		tm.FilePath = template.ServicePath
 	}
 
-	if err := tm.Generate(); err != nil {
+	change, err := tm.Change()
+	if err != nil {
 		return err
 	}
 
-	return nil
+	return apply(cmd, change)
 }
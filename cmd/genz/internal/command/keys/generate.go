@@ -0,0 +1,157 @@
+package keys
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	_PrivateKeyFile = "private_key.pem"
+	_PublicKeyFile  = "public_key.pem"
+)
+
+var (
+	alg     string
+	outDir  string
+	rsaBits int
+	force   bool
+)
+
+// algorithms maps the supported --alg values to the jwt.algorithm config value.
+var algorithms = map[string]string{
+	"rsa":        "RS256",
+	"ecdsa-p256": "ES256",
+	"ecdsa-p384": "ES384",
+	"ed25519":    "EdDSA",
+}
+
+var generateCmd = &cobra.Command{
+	Use:     "generate",
+	Short:   "Generate a new PEM encoded key pair.",
+	Args:    cobra.NoArgs,
+	Example: "genz keys generate --alg ed25519",
+	RunE:    runGenerateE,
+}
+
+func init() {
+	generateCmd.Flags().StringVarP(&alg, "alg", "a", "rsa", "Key algorithm: rsa, ecdsa-p256, ecdsa-p384 or ed25519.")
+	generateCmd.Flags().StringVarP(&outDir, "out", "o", ".", "Directory to write private_key.pem and public_key.pem to.")
+	generateCmd.Flags().IntVar(&rsaBits, "bits", 2048, "RSA key size in bits.")
+	generateCmd.Flags().BoolVarP(&force, "force", "f", false, "Overwrite existing key files.")
+}
+
+func runGenerateE(cmd *cobra.Command, _ []string) error {
+	jwtAlg, err := Generate(outDir, alg, rsaBits, force)
+	if err != nil {
+		return err
+	}
+
+	cmd.Printf("Generated %s and %s. Set jwt.algorithm to %s in your config.\n",
+		filepath.Join(outDir, _PrivateKeyFile), filepath.Join(outDir, _PublicKeyFile), jwtAlg)
+
+	return nil
+}
+
+// Generate writes a new key pair of the algorithm to private_key.pem and public_key.pem in
+// dir and returns the matching jwt.algorithm config value. Existing key files are only
+// replaced when overwrite is true.
+func Generate(dir, alg string, bits int, overwrite bool) (string, error) {
+	name := strings.ToLower(alg)
+	jwtAlg, ok := algorithms[name]
+	if !ok {
+		return "", fmt.Errorf("unsupported algorithm %q, use one of rsa, ecdsa-p256, ecdsa-p384 or ed25519", alg)
+	}
+
+	privPath := filepath.Join(dir, _PrivateKeyFile)
+	pubPath := filepath.Join(dir, _PublicKeyFile)
+
+	// Check both files up front, so that an existing public key does not leave a new
+	// private key behind that it does not match.
+	if !overwrite {
+		for _, path := range []string{privPath, pubPath} {
+			if _, err := os.Stat(path); err == nil {
+				return "", existsError(path)
+			}
+		}
+	}
+
+	privateKey, err := generateKey(name, bits)
+	if err != nil {
+		return "", err
+	}
+
+	privDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	pubDER, err := x509.MarshalPKIXPublicKey(privateKey.Public())
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", fmt.Errorf("failed to create directory %s", dir)
+	}
+
+	if err := writePEM(privPath, "PRIVATE KEY", privDER, 0600, overwrite); err != nil {
+		return "", err
+	}
+
+	if err := writePEM(pubPath, "PUBLIC KEY", pubDER, 0644, overwrite); err != nil {
+		os.Remove(privPath)
+		return "", err
+	}
+
+	return jwtAlg, nil
+}
+
+func generateKey(name string, bits int) (crypto.Signer, error) {
+	switch name {
+	case "ecdsa-p256":
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case "ecdsa-p384":
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case "ed25519":
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		return privateKey, err
+	default:
+		return rsa.GenerateKey(rand.Reader, bits)
+	}
+}
+
+func writePEM(path, blockType string, der []byte, perm os.FileMode, overwrite bool) error {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !overwrite {
+		flags |= os.O_EXCL
+	}
+
+	file, err := os.OpenFile(path, flags, perm)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return existsError(path)
+		}
+		return err
+	}
+
+	defer file.Close()
+
+	return pem.Encode(file, &pem.Block{Type: blockType, Bytes: der})
+}
+
+func existsError(path string) error {
+	return fmt.Errorf("%s already exists, use --force to overwrite it", path)
+}
@@ -0,0 +1,12 @@
+package keys
+
+import "github.com/spf13/cobra"
+
+var KeysCmd = &cobra.Command{
+	Use:   "keys",
+	Short: "Manage the key pairs used to sign JWTs.",
+}
+
+func init() {
+	KeysCmd.AddCommand(generateCmd)
+}
@@ -0,0 +1,96 @@
+package edit
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Change is the new content of a file. Old is nil for files that do not exist yet.
+type Change struct {
+	Path string
+	Old  []byte
+	New  []byte
+}
+
+// Create returns the change creating the file at path, which must not exist yet.
+func Create(path string, src []byte) (Change, error) {
+	if _, err := os.Stat(path); err == nil {
+		return Change{}, fmt.Errorf("file %s already exists", path)
+	}
+
+	return Change{Path: path, New: src}, nil
+}
+
+// Changed reports whether the change modifies the file.
+func (c Change) Changed() bool {
+	return c.Old == nil || !bytes.Equal(c.Old, c.New)
+}
+
+// Apply writes the changes to disk, creating missing directories.
+func Apply(changes ...Change) error {
+	for _, c := range changes {
+		if !c.Changed() {
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(c.Path), os.ModePerm); err != nil {
+			return fmt.Errorf("failed to create directory %s", filepath.Dir(c.Path))
+		}
+
+		if err := os.WriteFile(c.Path, c.New, 0o644); err != nil {
+			return fmt.Errorf("failed to write file %s", c.Path)
+		}
+	}
+
+	return nil
+}
+
+// Diff writes the changes as a unified diff, new files compared to /dev/null.
+func Diff(w io.Writer, changes ...Change) error {
+	for _, c := range changes {
+		if !c.Changed() {
+			continue
+		}
+
+		path := strings.TrimPrefix(filepath.ToSlash(c.Path), "/")
+
+		from := "a/" + path
+		if c.Old == nil {
+			from = "/dev/null"
+		}
+
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        splitLines(c.Old),
+			B:        splitLines(c.New),
+			FromFile: from,
+			ToFile:   "b/" + path,
+			Context:  3,
+		})
+		if err != nil {
+			return err
+		}
+
+		if _, err := io.WriteString(w, diff); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// splitLines splits the source after each newline, unlike difflib.SplitLines without
+// adding an empty line at the end.
+func splitLines(src []byte) []string {
+	lines := strings.SplitAfter(string(src), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
@@ -0,0 +1,91 @@
+package edit_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/geekswamp/zen/cmd/genz/internal/edit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	testCases := []struct {
+		name    string
+		changes []edit.Change
+		want    string
+	}{
+		{
+			name:    "New File",
+			changes: []edit.Change{{Path: "internal/model/product.go", New: []byte("package model\n\ntype Product struct{}\n")}},
+			want: "--- /dev/null\n+++ b/internal/model/product.go\n@@ -0,0 +1,3 @@\n" +
+				"+package model\n+\n+type Product struct{}\n",
+		},
+		{
+			name: "Updated File",
+			changes: []edit.Change{{
+				Path: "internal/router/router.go",
+				Old:  []byte("a\nb\nc\nd\ne\nf\ng\n"),
+				New:  []byte("a\nb\nc\nd\nx\ne\nf\ng\n"),
+			}},
+			want: "--- a/internal/router/router.go\n+++ b/internal/router/router.go\n@@ -2,6 +2,7 @@\n" +
+				" b\n c\n d\n+x\n e\n f\n g\n",
+		},
+		{
+			name:    "Unchanged File",
+			changes: []edit.Change{{Path: "go.mod", Old: []byte("module x\n"), New: []byte("module x\n")}},
+			want:    "",
+		},
+		{
+			name: "Several Files",
+			changes: []edit.Change{
+				{Path: "a.sql", New: []byte("SELECT 1;\n")},
+				{Path: "b.sql", Old: []byte("SELECT 1;\n"), New: []byte("SELECT 2;\n")},
+			},
+			want: "--- /dev/null\n+++ b/a.sql\n@@ -0,0 +1 @@\n+SELECT 1;\n" +
+				"--- a/b.sql\n+++ b/b.sql\n@@ -1 +1 @@\n-SELECT 1;\n+SELECT 2;\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			require.NoError(t, edit.Diff(&buf, tc.changes...))
+			assert.Equal(t, tc.want, buf.String())
+		})
+	}
+}
+
+func TestDiffOfEdit(t *testing.T) {
+	f := open(t, routerSrc)
+	require.NoError(t, f.AddStmts("RegisterRouter", `userGroup.POST("", create)`))
+
+	var buf bytes.Buffer
+	require.NoError(t, edit.Diff(&buf, f.Change()))
+
+	path := strings.TrimPrefix(filepath.ToSlash(f.Change().Path), "/")
+	want := "--- a/" + path + "\n+++ b/" + path + "\n@@ -7,4 +7,6 @@\n" +
+		" \n \tuserGroup := apiV1.Group(\"/user\")\n \tuserGroup.GET(\"\", list)\n+\n+\tuserGroup.POST(\"\", create)\n }\n"
+	assert.Equal(t, want, buf.String())
+}
+
+func TestCreateAndApply(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "internal", "model", "product.go")
+
+	change, err := edit.Create(path, []byte("package model\n"))
+	require.NoError(t, err)
+	assert.True(t, change.Changed())
+
+	require.NoError(t, edit.Apply(change))
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "package model\n", string(content))
+
+	_, err = edit.Create(path, []byte("package model\n"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,429 @@
+package edit
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// File is an existing Go source file edited in memory. Its edits locate their position
+// with go/ast, insert the new code there and format the file again with go/format.
+// Every edit is idempotent: code already present in the file is not inserted twice.
+type File struct {
+	path string
+	old  []byte
+	src  []byte
+}
+
+// Open reads the Go source file at path for editing.
+func Open(path string) (*File, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s", path)
+	}
+
+	return &File{path: path, old: src, src: src}, nil
+}
+
+// Change returns the edits made so far as a change of the file.
+func (f *File) Change() Change {
+	return Change{Path: f.path, Old: f.old, New: f.src}
+}
+
+// AddImport imports the package with the given path unless the file already does.
+func (f *File) AddImport(path string) error {
+	file, fset, err := f.parse()
+	if err != nil {
+		return err
+	}
+
+	for _, imp := range file.Imports {
+		if p, _ := strconv.Unquote(imp.Path.Value); p == path {
+			return nil
+		}
+	}
+
+	quoted := strconv.Quote(path)
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+
+		if gen.Rparen.IsValid() {
+			return f.insert(fset, gen.Rparen, "\t"+quoted+"\n")
+		}
+
+		return f.insert(fset, gen.End(), "\nimport "+quoted)
+	}
+
+	return f.insert(fset, file.Name.End(), "\n\nimport "+quoted+"\n")
+}
+
+// AddProviderSet declares the Wire provider set name with the given entries, or adds the
+// missing entries to the wire.NewSet call of the set when it is already declared.
+func (f *File) AddProviderSet(name string, entries ...string) error {
+	file, fset, err := f.parse()
+	if err != nil {
+		return err
+	}
+
+	spec := findValue(file, name)
+	if spec == nil {
+		set := "wire.NewSet(" + entries[0] + ")"
+		if len(entries) > 1 {
+			set = "wire.NewSet(\n\t" + strings.Join(entries, ",\n\t") + ",\n)"
+		}
+
+		return f.insert(fset, file.End(), "\n\nvar "+name+" = "+set+"\n")
+	}
+
+	call, ok := valueCall(spec)
+	if !ok {
+		return fmt.Errorf("%s in %s is not declared with a function call", name, f.path)
+	}
+
+	var missing []string
+	for _, entry := range entries {
+		if !slices.ContainsFunc(call.Args, func(arg ast.Expr) bool { return node(fset, arg) == entry }) {
+			missing = append(missing, entry)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	if fset.Position(call.Lparen).Line == fset.Position(call.Rparen).Line {
+		sep := ", "
+		if len(call.Args) == 0 {
+			sep = ""
+		}
+
+		return f.insert(fset, call.Rparen, sep+strings.Join(missing, ", "))
+	}
+
+	return f.insert(fset, call.Rparen, "\t"+strings.Join(missing, ",\n\t")+",\n")
+}
+
+// AddDecl appends the top level declarations of src to the file, skipping the ones whose
+// name is already declared.
+func (f *File) AddDecl(src string) error {
+	file, fset, err := f.parse()
+	if err != nil {
+		return err
+	}
+
+	snippet, snippetSet, err := parseSnippet("package p\n\n", src)
+	if err != nil {
+		return err
+	}
+
+	declared := declNames(file)
+
+	var missing []string
+	for _, decl := range snippet.Decls {
+		names := declNames(&ast.File{Decls: []ast.Decl{decl}})
+		if slices.ContainsFunc(names, func(name string) bool { return slices.Contains(declared, name) }) {
+			continue
+		}
+
+		missing = append(missing, source(snippetSet, snippet, decl, "package p\n\n"+src))
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return f.insert(fset, file.End(), "\n\n"+strings.Join(missing, "\n\n")+"\n")
+}
+
+// AddStmts appends the statements of src to the body of the top level function funcName,
+// skipping the ones already in the body.
+func (f *File) AddStmts(funcName, src string) error {
+	file, fset, err := f.parse()
+	if err != nil {
+		return err
+	}
+
+	fn := findFunc(file, funcName)
+	if fn == nil || fn.Body == nil {
+		return fmt.Errorf("function %s not found in %s", funcName, f.path)
+	}
+
+	prefix := "package p\n\nfunc _() {\n"
+	snippet, snippetSet, err := parseSnippet(prefix, src+"\n}\n")
+	if err != nil {
+		return err
+	}
+
+	existing := make([]string, 0, len(fn.Body.List))
+	for _, stmt := range fn.Body.List {
+		existing = append(existing, node(fset, stmt))
+	}
+
+	body := snippet.Decls[0].(*ast.FuncDecl).Body.List
+
+	var missing []ast.Stmt
+	for _, stmt := range body {
+		if !slices.Contains(existing, node(snippetSet, stmt)) {
+			missing = append(missing, stmt)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	// A missing snippet is added as a block of its own, keeping its blank lines, while the
+	// missing statements of a partly present snippet continue the last block.
+	text := "\n" + strings.TrimSpace(src)
+	if len(missing) < len(body) {
+		lines := make([]string, 0, len(missing))
+		for _, stmt := range missing {
+			lines = append(lines, source(snippetSet, snippet, stmt, prefix+src+"\n}\n"))
+		}
+		text = strings.Join(lines, "\n")
+	}
+
+	return f.insert(fset, fn.Body.Rbrace, text+"\n")
+}
+
+// FieldNames returns the names of the fields of the struct type typeName.
+func (f *File) FieldNames(typeName string) ([]string, error) {
+	file, _, err := f.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	st := findStruct(file, typeName)
+	if st == nil {
+		return nil, fmt.Errorf("struct %s not found in %s", typeName, f.path)
+	}
+
+	return fieldNames(st), nil
+}
+
+// AddFields adds the fields of src to the struct type typeName, skipping the fields
+// whose names the struct already has.
+func (f *File) AddFields(typeName, src string) error {
+	file, fset, err := f.parse()
+	if err != nil {
+		return err
+	}
+
+	st := findStruct(file, typeName)
+	if st == nil {
+		return fmt.Errorf("struct %s not found in %s", typeName, f.path)
+	}
+
+	names := fieldNames(st)
+
+	prefix := "package p\n\ntype _ struct {\n"
+	snippet, snippetSet, err := parseSnippet(prefix, src+"\n}\n")
+	if err != nil {
+		return err
+	}
+
+	fields := findStruct(snippet, "_").Fields.List
+
+	var missing []string
+	for _, field := range fields {
+		if slices.ContainsFunc(field.Names, func(name *ast.Ident) bool { return slices.Contains(names, name.Name) }) {
+			continue
+		}
+
+		missing = append(missing, source(snippetSet, snippet, field, prefix+src+"\n}\n"))
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	text := strings.Join(missing, "\n") + "\n"
+	if fset.Position(st.Fields.Opening).Line == fset.Position(st.Fields.Closing).Line {
+		text = "\n" + text
+	}
+
+	return f.insert(fset, st.Fields.Closing, text)
+}
+
+func (f *File) parse() (*ast.File, *token.FileSet, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, f.path, f.src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s: %v", f.path, err)
+	}
+
+	return file, fset, nil
+}
+
+// insert adds text at the position and formats the file.
+func (f *File) insert(fset *token.FileSet, pos token.Pos, text string) error {
+	offset := fset.Position(pos).Offset
+
+	var buf bytes.Buffer
+	buf.Write(f.src[:offset])
+	buf.WriteString(text)
+	buf.Write(f.src[offset:])
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to format %s: %v", f.path, err)
+	}
+
+	f.src = src
+
+	return nil
+}
+
+func parseSnippet(prefix, src string) (*ast.File, *token.FileSet, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", prefix+src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse generated code: %v", err)
+	}
+
+	return file, fset, nil
+}
+
+// node prints the node without comments, to compare code regardless of its formatting.
+func node(fset *token.FileSet, n ast.Node) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, n); err != nil {
+		return ""
+	}
+
+	return buf.String()
+}
+
+// source returns the text of the node in src, including the doc comment of declarations.
+func source(fset *token.FileSet, file *ast.File, n ast.Node, src string) string {
+	start := n.Pos()
+	switch decl := n.(type) {
+	case *ast.FuncDecl:
+		if decl.Doc != nil {
+			start = decl.Doc.Pos()
+		}
+	case *ast.GenDecl:
+		if decl.Doc != nil {
+			start = decl.Doc.Pos()
+		}
+	case *ast.Field:
+		if decl.Doc != nil {
+			start = decl.Doc.Pos()
+		}
+	}
+
+	end := n.End()
+	if field, ok := n.(*ast.Field); ok && field.Comment != nil {
+		end = field.Comment.End()
+	}
+
+	tf := fset.File(file.Pos())
+
+	return src[tf.Offset(start):tf.Offset(end)]
+}
+
+func declNames(file *ast.File) []string {
+	var names []string
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv == nil {
+				names = append(names, decl.Name.Name)
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						names = append(names, name.Name)
+					}
+				case *ast.TypeSpec:
+					names = append(names, spec.Name.Name)
+				}
+			}
+		}
+	}
+
+	return names
+}
+
+func findFunc(file *ast.File, name string) *ast.FuncDecl {
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == name {
+			return fn
+		}
+	}
+
+	return nil
+}
+
+func findValue(file *ast.File, name string) *ast.ValueSpec {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			value := spec.(*ast.ValueSpec)
+			if len(value.Names) == 1 && value.Names[0].Name == name {
+				return value
+			}
+		}
+	}
+
+	return nil
+}
+
+func valueCall(spec *ast.ValueSpec) (*ast.CallExpr, bool) {
+	if len(spec.Values) != 1 {
+		return nil, false
+	}
+
+	call, ok := spec.Values[0].(*ast.CallExpr)
+	return call, ok
+}
+
+func fieldNames(st *ast.StructType) []string {
+	var names []string
+	for _, field := range st.Fields.List {
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+	}
+
+	return names
+}
+
+func findStruct(file *ast.File, name string) *ast.StructType {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			if typeSpec.Name.Name != name {
+				continue
+			}
+
+			if st, ok := typeSpec.Type.(*ast.StructType); ok {
+				return st
+			}
+		}
+	}
+
+	return nil
+}
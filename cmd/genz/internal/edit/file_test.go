@@ -0,0 +1,310 @@
+package edit_test
+
+import (
+	"go/format"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/geekswamp/zen/cmd/genz/internal/edit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const routerSrc = `package router
+
+import "github.com/gin-gonic/gin"
+
+func RegisterRouter(r *gin.Engine) {
+	apiV1 := r.Group("/api/v1")
+
+	userGroup := apiV1.Group("/user")
+	userGroup.GET("", list)
+}
+`
+
+const diSrc = `package di
+
+import (
+	"github.com/acme/app/internal/service"
+	"github.com/google/wire"
+)
+
+var UserServiceSet = wire.NewSet(service.NewUserService)
+
+var AuthServiceSet = wire.NewSet(
+	KeySet,
+	service.NewAuthService,
+)
+`
+
+const modelSrc = `package model
+
+type Product struct {
+	Title string ` + "`gorm:\"column:title\"`" + `
+}
+
+type Empty struct{}
+`
+
+const productRoutes = `productGroup := apiV1.Group("/product")
+
+productGroup.GET("", list)
+productGroup.POST("", create)`
+
+func TestFileEdits(t *testing.T) {
+	testCases := []struct {
+		name string
+		src  string
+		edit func(f *edit.File) error
+		want string
+	}{
+		{
+			name: "Import Into Group",
+			src:  diSrc,
+			edit: func(f *edit.File) error { return f.AddImport("github.com/acme/app/internal/repository") },
+			want: `package di
+
+import (
+	"github.com/acme/app/internal/repository"
+	"github.com/acme/app/internal/service"
+	"github.com/google/wire"
+)
+
+var UserServiceSet = wire.NewSet(service.NewUserService)
+
+var AuthServiceSet = wire.NewSet(
+	KeySet,
+	service.NewAuthService,
+)
+`,
+		},
+		{
+			name: "Import After Single Import",
+			src:  routerSrc,
+			edit: func(f *edit.File) error { return f.AddImport("github.com/acme/app/internal/di") },
+			want: `package router
+
+import "github.com/gin-gonic/gin"
+import "github.com/acme/app/internal/di"
+
+func RegisterRouter(r *gin.Engine) {
+	apiV1 := r.Group("/api/v1")
+
+	userGroup := apiV1.Group("/user")
+	userGroup.GET("", list)
+}
+`,
+		},
+		{
+			name: "Import Without Imports",
+			src:  "package model\n\ntype Empty struct{}\n",
+			edit: func(f *edit.File) error { return f.AddImport("github.com/google/uuid") },
+			want: "package model\n\nimport \"github.com/google/uuid\"\n\ntype Empty struct{}\n",
+		},
+		{
+			name: "New Provider Set",
+			src:  diSrc,
+			edit: func(f *edit.File) error {
+				return f.AddProviderSet("ProductServiceSet", "service.NewProductService")
+			},
+			want: diSrc + "\nvar ProductServiceSet = wire.NewSet(service.NewProductService)\n",
+		},
+		{
+			name: "New Multi Line Provider Set",
+			src:  diSrc,
+			edit: func(f *edit.File) error {
+				return f.AddProviderSet("ProductRepositorySet", "PostgresSet", "repository.NewProductRepo")
+			},
+			want: diSrc + "\nvar ProductRepositorySet = wire.NewSet(\n\tPostgresSet,\n\trepository.NewProductRepo,\n)\n",
+		},
+		{
+			name: "Extend Single Line Provider Set",
+			src:  diSrc,
+			edit: func(f *edit.File) error {
+				return f.AddProviderSet("UserServiceSet", "service.NewUserService", "KeySet")
+			},
+			want: `package di
+
+import (
+	"github.com/acme/app/internal/service"
+	"github.com/google/wire"
+)
+
+var UserServiceSet = wire.NewSet(service.NewUserService, KeySet)
+
+var AuthServiceSet = wire.NewSet(
+	KeySet,
+	service.NewAuthService,
+)
+`,
+		},
+		{
+			name: "Extend Multi Line Provider Set",
+			src:  diSrc,
+			edit: func(f *edit.File) error {
+				return f.AddProviderSet("AuthServiceSet", "KeySet", "MailSet")
+			},
+			want: `package di
+
+import (
+	"github.com/acme/app/internal/service"
+	"github.com/google/wire"
+)
+
+var UserServiceSet = wire.NewSet(service.NewUserService)
+
+var AuthServiceSet = wire.NewSet(
+	KeySet,
+	service.NewAuthService,
+	MailSet,
+)
+`,
+		},
+		{
+			name: "Declaration",
+			src:  diSrc,
+			edit: func(f *edit.File) error {
+				return f.AddDecl("// InitProduct builds the product service.\nfunc InitProduct() {\n\twire.Build(ProductServiceSet)\n}\n\nvar UserServiceSet = 1")
+			},
+			want: diSrc + "\n// InitProduct builds the product service.\nfunc InitProduct() {\n\twire.Build(ProductServiceSet)\n}\n",
+		},
+		{
+			name: "Routes",
+			src:  routerSrc,
+			edit: func(f *edit.File) error { return f.AddStmts("RegisterRouter", productRoutes) },
+			want: `package router
+
+import "github.com/gin-gonic/gin"
+
+func RegisterRouter(r *gin.Engine) {
+	apiV1 := r.Group("/api/v1")
+
+	userGroup := apiV1.Group("/user")
+	userGroup.GET("", list)
+
+	productGroup := apiV1.Group("/product")
+
+	productGroup.GET("", list)
+	productGroup.POST("", create)
+}
+`,
+		},
+		{
+			name: "Missing Statements Only",
+			src:  routerSrc,
+			edit: func(f *edit.File) error {
+				return f.AddStmts("RegisterRouter", "userGroup := apiV1.Group(\"/user\")\nuserGroup.POST(\"\", create)")
+			},
+			want: `package router
+
+import "github.com/gin-gonic/gin"
+
+func RegisterRouter(r *gin.Engine) {
+	apiV1 := r.Group("/api/v1")
+
+	userGroup := apiV1.Group("/user")
+	userGroup.GET("", list)
+	userGroup.POST("", create)
+}
+`,
+		},
+		{
+			name: "Struct Fields",
+			src:  modelSrc,
+			edit: func(f *edit.File) error {
+				return f.AddFields("Product", "Title string\nPrice int64 `gorm:\"column:price\"` // in cents")
+			},
+			want: `package model
+
+type Product struct {
+	Title string ` + "`gorm:\"column:title\"`" + `
+	Price int64  ` + "`gorm:\"column:price\"`" + ` // in cents
+}
+
+type Empty struct{}
+`,
+		},
+		{
+			name: "Fields Of Empty Struct",
+			src:  modelSrc,
+			edit: func(f *edit.File) error { return f.AddFields("Empty", "Name string") },
+			want: `package model
+
+type Product struct {
+	Title string ` + "`gorm:\"column:title\"`" + `
+}
+
+type Empty struct {
+	Name string
+}
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := open(t, tc.src)
+
+			require.NoError(t, tc.edit(f))
+			got := f.Change().New
+			assert.Equal(t, tc.want, string(got))
+			assert.True(t, f.Change().Changed())
+
+			formatted, err := format.Source(got)
+			require.NoError(t, err)
+			assert.Equal(t, string(formatted), string(got), "output is not gofmt clean")
+
+			require.NoError(t, tc.edit(f))
+			assert.Equal(t, string(got), string(f.Change().New), "second pass changed the file")
+
+			again := open(t, string(got))
+			require.NoError(t, tc.edit(again))
+			assert.False(t, again.Change().Changed(), "edit of the written file changed it")
+		})
+	}
+}
+
+func TestFileEditErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		edit func(f *edit.File) error
+	}{
+		{name: "Unknown Function", edit: func(f *edit.File) error { return f.AddStmts("Missing", "x := 1") }},
+		{name: "Unknown Struct", edit: func(f *edit.File) error { return f.AddFields("Missing", "Name string") }},
+		{name: "Invalid Snippet", edit: func(f *edit.File) error { return f.AddStmts("RegisterRouter", "x :=") }},
+		{name: "Provider Set Without Call", edit: func(f *edit.File) error { return f.AddProviderSet("x", "y") }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := open(t, routerSrc+"\nvar x = 1\n")
+
+			assert.Error(t, tc.edit(f))
+			assert.False(t, f.Change().Changed())
+		})
+	}
+}
+
+func TestFieldNames(t *testing.T) {
+	f := open(t, modelSrc)
+
+	names, err := f.FieldNames("Product")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Title"}, names)
+
+	_, err = f.FieldNames("Missing")
+	assert.Error(t, err)
+}
+
+func open(t *testing.T, src string) *edit.File {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "file.go")
+	require.NoError(t, os.WriteFile(path, []byte(src), 0o644))
+
+	f, err := edit.Open(path)
+	require.NoError(t, err)
+
+	return f
+}
@@ -1,6 +1,10 @@
 package format
 
-import "github.com/iancoleman/strcase"
+import (
+	"strings"
+
+	"github.com/iancoleman/strcase"
+)
 
 func ToCamelCase(text string) string {
 	return strcase.ToLowerCamel(text)
@@ -13,3 +17,12 @@ func ToPascalCase(text string) string {
 func ToSnakeCase(text string) string {
 	return strcase.ToSnake(text)
 }
+
+func ToKebabCase(text string) string {
+	return strcase.ToKebab(text)
+}
+
+// ToPackageName returns the text as a Go package name, lower cased without separators.
+func ToPackageName(text string) string {
+	return strings.ToLower(strcase.ToCamel(text))
+}
@@ -0,0 +1,134 @@
+package skeleton
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// skipped are the files and directories of the skeleton that are not copied: the ones
+// belonging to the Zen repository itself, local output and the Makefile, which is
+// rendered for the project instead.
+var skipped = []string{".git", ".github", "tmp", "Makefile", "README.md"}
+
+// Copy copies the skeleton in src to dst, replacing the module path from with to in go.mod
+// and in the imports of every Go file. Nested modules, such as cmd/genz, are left out.
+func Copy(src, dst, from, to string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		if rel == "." {
+			return os.MkdirAll(target, 0o755)
+		}
+
+		if slices.Contains(skipped, filepath.ToSlash(rel)) || filepath.Ext(rel) == ".pem" {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if d.IsDir() {
+			if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(target, 0o755)
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case rel == "go.mod":
+			data, err = rewriteModule(data, to)
+		case filepath.Ext(rel) == ".go":
+			data, err = rewriteImports(rel, data, from, to)
+		}
+		if err != nil {
+			return err
+		}
+
+		// Files of the module cache are read-only.
+		return os.WriteFile(target, data, info.Mode().Perm()|0o200)
+	})
+}
+
+func rewriteModule(data []byte, to string) ([]byte, error) {
+	file, err := modfile.Parse("go.mod", data, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := file.AddModuleStmt(to); err != nil {
+		return nil, err
+	}
+
+	return file.Format()
+}
+
+// rewriteImports replaces the module path from with to in the imports of the Go source.
+func rewriteImports(name string, data []byte, from, to string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, name, data, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", name, err)
+	}
+
+	var changed bool
+	for _, imp := range file.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		if path != from && !strings.HasPrefix(path, from+"/") {
+			continue
+		}
+
+		imp.Path.Value = strconv.Quote(to + strings.TrimPrefix(path, from))
+		changed = true
+	}
+
+	if !changed {
+		return data, nil
+	}
+
+	ast.SortImports(fset, file)
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return nil, fmt.Errorf("failed to format %s: %v", name, err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,57 @@
+package skeleton
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+//go:embed Makefile.tmpl
+var makefile string
+
+// WriteMakefile renders the Makefile of the project and ignores the bin directory it builds to.
+func WriteMakefile(dir, name string) error {
+	t, err := template.New("Makefile").Parse(makefile)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, "Makefile")
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file %s", path)
+	}
+	defer f.Close()
+
+	if err := t.Execute(f, map[string]any{"Name": name}); err != nil {
+		return err
+	}
+
+	return ignore(dir, "bin/")
+}
+
+// ignore adds the pattern to the .gitignore of the project unless it is listed already.
+func ignore(dir, pattern string) error {
+	path := filepath.Join(dir, ".gitignore")
+
+	src, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	lines := strings.Split(string(src), "\n")
+	for _, line := range lines {
+		if strings.TrimSpace(line) == pattern {
+			return nil
+		}
+	}
+
+	if len(src) > 0 && !strings.HasSuffix(string(src), "\n") {
+		src = append(src, '\n')
+	}
+
+	return os.WriteFile(path, append(src, pattern+"\n"...), 0o644)
+}
@@ -0,0 +1,58 @@
+package skeleton
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"golang.org/x/mod/modfile"
+)
+
+// Module is the module path of the Zen skeleton new projects are copied from.
+const Module = "github.com/geekswamp/zen"
+
+// Download downloads the version of the Zen module to the module cache and returns its directory.
+func Download(version string) (string, error) {
+	download := exec.Command("go", "mod", "download", "-json", Module+"@"+version)
+	// Outside of a module, so that the go.mod of the working directory is left alone.
+	download.Dir = os.TempDir()
+
+	out, err := download.Output()
+
+	var info struct {
+		Dir   string
+		Error string
+	}
+	if jsonErr := json.Unmarshal(out, &info); jsonErr != nil {
+		if err != nil {
+			return "", fmt.Errorf("failed to download %s@%s: %v", Module, version, err)
+		}
+		return "", jsonErr
+	}
+
+	if info.Error != "" {
+		return "", errors.New(info.Error)
+	}
+
+	return info.Dir, nil
+}
+
+// ModulePath returns the module path declared by the go.mod in dir.
+func ModulePath(dir string) (string, error) {
+	path := filepath.Join(dir, "go.mod")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s, is %s a Go module?", path, dir)
+	}
+
+	modPath := modfile.ModulePath(data)
+	if modPath == "" {
+		return "", fmt.Errorf("no module declared in %s", path)
+	}
+
+	return modPath, nil
+}
@@ -0,0 +1,114 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ConfigFile is the file of a project declaring its settings and custom generators.
+	ConfigFile = ".genz.yaml"
+
+	// DefaultDir is the directory of a project whose templates override the embedded ones.
+	DefaultDir = ".genz/templates"
+)
+
+var (
+	// Dir is looked up for a template by name before the embedded templates.
+	Dir = DefaultDir
+
+	// Vars are user supplied variables, available to every template as .Vars.
+	Vars = map[string]string{}
+)
+
+// Config is the content of ConfigFile, e.g.
+//
+//	template-dir: tools/templates
+//	vars:
+//	  team: payments
+//	generators:
+//	  event:
+//	    short: Create a new event.
+//	    template: event
+//	    dir: internal/event
+//	    suffix: _event
+type Config struct {
+	TemplateDir string               `yaml:"template-dir"`
+	Vars        map[string]string    `yaml:"vars"`
+	Generators  map[string]Generator `yaml:"generators"`
+}
+
+// Generator is a user defined kind of file, created with genz create <kind> <name>.
+type Generator struct {
+	Short string `yaml:"short"`
+
+	// Template is the name of the template without .tmpl, looked up in Dir first.
+	Template string `yaml:"template"`
+
+	Dir    string `yaml:"dir"`
+	Suffix string `yaml:"suffix"`
+
+	// Ext is the extension of the generated file, ".go" when empty.
+	Ext string `yaml:"ext"`
+}
+
+// LoadConfig reads the config file at path, which is optional.
+func LoadConfig(path string) (Config, error) {
+	var config Config
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return config, fmt.Errorf("failed to read file %s", path)
+	}
+
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+
+	for kind, g := range config.Generators {
+		if g.Template == "" || g.Dir == "" {
+			return config, fmt.Errorf("generator %s in %s needs a template and a dir", kind, path)
+		}
+	}
+
+	return config, nil
+}
+
+// Make returns the file of the generator for the feature name.
+func (g Generator) Make(name string) Make {
+	ext := g.Ext
+	if ext != "" && !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	if ext == ".go" {
+		ext = ""
+	}
+
+	return Make{
+		FilePath:    FilePath(g.Dir),
+		FileType:    fileType(strings.TrimSuffix(g.Template, ".tmpl")),
+		SuffixFile:  suffix(g.Suffix),
+		FeatureName: name,
+		Ext:         ext,
+	}
+}
+
+// lookup returns the file system holding the template, Dir when it overrides the embedded one.
+func lookup(name string) fs.FS {
+	if Dir != "" {
+		if _, err := os.Stat(filepath.Join(Dir, name)); err == nil {
+			return os.DirFS(Dir)
+		}
+	}
+
+	return TemplateFile
+}
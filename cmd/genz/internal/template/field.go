@@ -0,0 +1,163 @@
+package template
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/geekswamp/zen/cmd/genz/internal/format"
+)
+
+// fieldType describes how a field type given on the command line is generated.
+type fieldType struct {
+	goType     string
+	sqlType    string
+	sqlDefault string
+	validate   string
+	sample     string
+}
+
+var fieldTypes = map[string]fieldType{
+	"string":  {goType: "string", sqlType: "varchar", sqlDefault: "''", validate: "required,max=255", sample: `"example"`},
+	"text":    {goType: "string", sqlType: "text", sqlDefault: "''", validate: "required", sample: `"example"`},
+	"int":     {goType: "int", sqlType: "integer", sqlDefault: "0", sample: "1"},
+	"int32":   {goType: "int32", sqlType: "integer", sqlDefault: "0", sample: "1"},
+	"int64":   {goType: "int64", sqlType: "bigint", sqlDefault: "0", sample: "1"},
+	"float64": {goType: "float64", sqlType: "double precision", sqlDefault: "0", sample: "1.5"},
+	"bool":    {goType: "bool", sqlType: "boolean", sqlDefault: "FALSE", sample: "true"},
+	"uuid":    {goType: "uuid.UUID", sqlType: "uuid", sqlDefault: "'00000000-0000-0000-0000-000000000000'", validate: "required", sample: `"0b5c1d6e-8f3a-4b2c-9d7e-1a2b3c4d5e6f"`},
+}
+
+// initialisms are the words of field names written in upper case in Go names, as in OwnerID.
+var initialisms = []string{"api", "html", "http", "id", "ip", "json", "sql", "uri", "url", "uuid"}
+
+// reservedFields are the columns of base.Model, which every generated model embeds.
+var reservedFields = []string{"id", "created_time", "updated_time", "deleted_time", "version"}
+
+// Field is a column of a generated resource, declared as name:type on the command line.
+type Field struct {
+	Name string
+	Type string
+}
+
+// ParseFields parses a comma separated list of name:type pairs, e.g. "title:string,price:int64".
+func ParseFields(spec string) ([]Field, error) {
+	var fields []Field
+
+	for pair := range strings.SplitSeq(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		name, typ, found := strings.Cut(pair, ":")
+		name = format.ToSnakeCase(strings.TrimSpace(name))
+		typ = strings.ToLower(strings.TrimSpace(typ))
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid field %q, use name:type", pair)
+		}
+
+		if _, ok := fieldTypes[typ]; !ok {
+			return nil, fmt.Errorf("unsupported type %q of field %s, use one of %s", typ, name, strings.Join(FieldTypes(), ", "))
+		}
+
+		if slices.Contains(reservedFields, name) {
+			return nil, fmt.Errorf("field %s is already declared by base.Model", name)
+		}
+
+		if slices.ContainsFunc(fields, func(f Field) bool { return f.Name == name }) {
+			return nil, fmt.Errorf("field %s is declared twice", name)
+		}
+
+		fields = append(fields, Field{Name: name, Type: typ})
+	}
+
+	return fields, nil
+}
+
+// FieldTypes returns the supported field types, sorted.
+func FieldTypes() []string {
+	types := make([]string, 0, len(fieldTypes))
+	for typ := range fieldTypes {
+		types = append(types, typ)
+	}
+	slices.Sort(types)
+
+	return types
+}
+
+func (f Field) GoName() string {
+	var b strings.Builder
+	for word := range strings.SplitSeq(f.Name, "_") {
+		if slices.Contains(initialisms, word) {
+			b.WriteString(strings.ToUpper(word))
+			continue
+		}
+		b.WriteString(format.ToPascalCase(word))
+	}
+
+	return b.String()
+}
+
+func (f Field) GoType() string {
+	return fieldTypes[f.Type].goType
+}
+
+func (f Field) SQLType() string {
+	return strings.ToUpper(fieldTypes[f.Type].sqlType)
+}
+
+// SQLDefault returns the zero value of the field, which fills the column of existing rows when it is added to a table.
+func (f Field) SQLDefault() string {
+	return fieldTypes[f.Type].sqlDefault
+}
+
+func (f Field) GormTag() string {
+	return fmt.Sprintf("column:%s;type:%s;not null", f.Name, fieldTypes[f.Type].sqlType)
+}
+
+// CreateValidate returns the validate tag of the field in create requests.
+func (f Field) CreateValidate() string {
+	return fieldTypes[f.Type].validate
+}
+
+// UpdateValidate returns the validate tag of the field in update requests, where every field is optional.
+func (f Field) UpdateValidate() string {
+	rules, _ := strings.CutPrefix(fieldTypes[f.Type].validate, "required")
+	rules = strings.TrimPrefix(rules, ",")
+	if rules == "" {
+		return ""
+	}
+
+	return "omitempty," + rules
+}
+
+// Sample returns a JSON value of the field passing the create validation.
+func (f Field) Sample() string {
+	return fieldTypes[f.Type].sample
+}
+
+// HasUUID reports whether any of the fields is a uuid, which generated files must import.
+func HasUUID(fields []Field) bool {
+	return slices.ContainsFunc(fields, func(f Field) bool { return f.Type == "uuid" })
+}
+
+// sampleJSON returns a create request body with a sample value for every field.
+func sampleJSON(fields []Field) string {
+	values := make([]string, 0, len(fields))
+	for _, f := range fields {
+		values = append(values, fmt.Sprintf("%q:%s", f.Name, f.Sample()))
+	}
+
+	return "{" + strings.Join(values, ",") + "}"
+}
+
+// columnWidth returns the width of the widest column of a generated table.
+func columnWidth(fields []Field) int {
+	width := len("created_time")
+	for _, f := range fields {
+		width = max(width, len(f.Name))
+	}
+
+	return width
+}
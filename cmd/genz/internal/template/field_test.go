@@ -0,0 +1,102 @@
+package template_test
+
+import (
+	"testing"
+
+	"github.com/geekswamp/zen/cmd/genz/internal/template"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFields(t *testing.T) {
+	testCases := []struct {
+		name    string
+		spec    string
+		want    []template.Field
+		wantErr bool
+	}{
+		{name: "Empty", spec: "", want: nil},
+		{
+			name: "Several Fields",
+			spec: "title:string,price:int64,owner_id:uuid",
+			want: []template.Field{{Name: "title", Type: "string"}, {Name: "price", Type: "int64"}, {Name: "owner_id", Type: "uuid"}},
+		},
+		{
+			name: "Spaces, Case And Trailing Comma",
+			spec: " ownerId : UUID , active:bool,",
+			want: []template.Field{{Name: "owner_id", Type: "uuid"}, {Name: "active", Type: "bool"}},
+		},
+		{name: "Missing Type", spec: "title", wantErr: true},
+		{name: "Missing Name", spec: ":string", wantErr: true},
+		{name: "Unsupported Type", spec: "title:varchar", wantErr: true},
+		{name: "Reserved Field", spec: "created_time:int64", wantErr: true},
+		{name: "Duplicate Field", spec: "title:string,Title:text", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fields, err := template.ParseFields(tc.spec)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, fields)
+		})
+	}
+}
+
+func TestField(t *testing.T) {
+	testCases := []struct {
+		field          template.Field
+		goName         string
+		goType         string
+		gormTag        string
+		sqlDefault     string
+		createValidate string
+		updateValidate string
+	}{
+		{
+			field:          template.Field{Name: "title", Type: "string"},
+			goName:         "Title",
+			goType:         "string",
+			gormTag:        "column:title;type:varchar;not null",
+			sqlDefault:     "''",
+			createValidate: "required,max=255",
+			updateValidate: "omitempty,max=255",
+		},
+		{
+			field:          template.Field{Name: "owner_id", Type: "uuid"},
+			goName:         "OwnerID",
+			goType:         "uuid.UUID",
+			gormTag:        "column:owner_id;type:uuid;not null",
+			sqlDefault:     "'00000000-0000-0000-0000-000000000000'",
+			createValidate: "required",
+		},
+		{
+			field:      template.Field{Name: "api_url", Type: "float64"},
+			goName:     "APIURL",
+			goType:     "float64",
+			gormTag:    "column:api_url;type:double precision;not null",
+			sqlDefault: "0",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.field.Name, func(t *testing.T) {
+			assert.Equal(t, tc.goName, tc.field.GoName())
+			assert.Equal(t, tc.goType, tc.field.GoType())
+			assert.Equal(t, tc.gormTag, tc.field.GormTag())
+			assert.Equal(t, tc.sqlDefault, tc.field.SQLDefault())
+			assert.Equal(t, tc.createValidate, tc.field.CreateValidate())
+			assert.Equal(t, tc.updateValidate, tc.field.UpdateValidate())
+		})
+	}
+}
+
+func TestHasUUID(t *testing.T) {
+	assert.False(t, template.HasUUID(nil))
+	assert.False(t, template.HasUUID([]template.Field{{Name: "title", Type: "string"}}))
+	assert.True(t, template.HasUUID([]template.Field{{Name: "title", Type: "string"}, {Name: "owner_id", Type: "uuid"}}))
+}
@@ -1,36 +1,61 @@
 package template
 
 import (
-	"errors"
+	"bytes"
 	"fmt"
-	"os"
+	goformat "go/format"
 	"path/filepath"
 
+	"github.com/geekswamp/zen/cmd/genz/internal/edit"
 	"github.com/geekswamp/zen/cmd/genz/internal/format"
 )
 
-func (m Make) Generate() error {
-	path := filepath.Join(filepath.Clean(string(m.FilePath)), format.ToSnakeCase(string(m.FeatureName))+string(m.SuffixFile)+".go")
-	dir := filepath.Dir(path)
+// Change renders the template as the change creating the file at Path.
+func (m Make) Change() (edit.Change, error) {
+	src, err := m.Render()
+	if err != nil {
+		return edit.Change{}, err
+	}
 
-	if _, err := os.Stat(path); err == nil {
-		return errors.New("file already exists")
+	if m.Ext == "" {
+		if src, err = formatSource(src); err != nil {
+			return edit.Change{}, err
+		}
 	}
 
-	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
-		return fmt.Errorf("failed to create directory %s", dir)
+	return edit.Create(m.Path(), src)
+}
+
+// Path returns the path of the file generated by Change.
+func (m Make) Path() string {
+	name := m.FileName
+	if name == "" {
+		name = format.ToSnakeCase(string(m.FeatureName)) + string(m.SuffixFile)
 	}
 
-	file, err := os.Create(path)
+	ext := m.Ext
+	if ext == "" {
+		ext = ".go"
+	}
+
+	return filepath.Join(filepath.Clean(string(m.FilePath)), name+ext)
+}
+
+func formatSource(src []byte) ([]byte, error) {
+	formatted, err := goformat.Source(src)
 	if err != nil {
-		return errors.New("failed to create model")
+		return nil, fmt.Errorf("failed to format generated file %v", err)
 	}
 
-	defer file.Close()
+	return formatted, nil
+}
 
-	if err := m.Parse(file); err != nil {
-		return fmt.Errorf("failed to execute template file %v", err)
+// Render executes the template, e.g. to insert the code into an existing file.
+func (m Make) Render() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := m.Parse(&buf); err != nil {
+		return nil, fmt.Errorf("failed to execute template file %v", err)
 	}
 
-	return nil
+	return buf.Bytes(), nil
 }
@@ -0,0 +1,34 @@
+package template
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+)
+
+var migrationPattern = regexp.MustCompile(`^(\d+)_\w+\.(up|down)\.sql$`)
+
+// NextMigration returns the zero padded version following the highest migration in the directory.
+func NextMigration(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	var latest int64
+	for _, entry := range entries {
+		match := migrationPattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return "", err
+		}
+		latest = max(latest, version)
+	}
+
+	return fmt.Sprintf("%04d", latest+1), nil
+}
@@ -2,15 +2,16 @@ package template
 
 import (
 	"fmt"
-	"os"
+	"io"
 	"reflect"
 	"text/template"
 
 	"github.com/geekswamp/zen/cmd/genz/internal/format"
 	"github.com/geekswamp/zen/cmd/genz/internal/mod"
+	"github.com/jinzhu/inflection"
 )
 
-func (m Make) Parse(file *os.File) error {
+func (m Make) Parse(w io.Writer) error {
 	tmplFile := fmt.Sprintf("%s.tmpl", m.FileType)
 
 	funcMap := template.FuncMap{
@@ -20,11 +21,15 @@ func (m Make) Parse(file *os.File) error {
 		"ToPascalCase": func(text string) string {
 			return format.ToPascalCase(text)
 		},
+		"snake":    format.ToSnakeCase,
+		"kebab":    format.ToKebabCase,
+		"plural":   inflection.Plural,
+		"singular": inflection.Singular,
 	}
 
-	t, err := template.New(tmplFile).Funcs(funcMap).ParseFS(TemplateFile, tmplFile)
+	t, err := template.New(tmplFile).Funcs(funcMap).ParseFS(lookup(tmplFile), tmplFile)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to parse template %s: %v", tmplFile, err)
 	}
 
 	modName, err := mod.GetModuleName()
@@ -35,9 +40,18 @@ func (m Make) Parse(file *os.File) error {
 	data := map[string]any{
 		"Module":     modName,
 		"StructName": m.FeatureName,
+		"Package":    format.ToPackageName(m.FeatureName),
+		"RoutePath":  format.ToKebabCase(m.FeatureName),
+		"Entity":     format.ToSnakeCase(m.FeatureName),
+		"Table":      inflection.Plural(format.ToSnakeCase(m.FeatureName)),
+		"Fields":     m.Fields,
+		"HasUUID":    HasUUID(m.Fields),
+		"SampleJSON": sampleJSON(m.Fields),
+		"Width":      columnWidth(m.Fields),
+		"Vars":       Vars,
 	}
 
-	if err := t.Execute(file, data); err != nil {
+	if err := t.Execute(w, data); err != nil {
 		return err
 	}
 
@@ -9,7 +9,15 @@ const (
 	Router     fileType = "router"
 	Service    fileType = "service"
 	Handler    fileType = "handler"
+	Types      fileType = "handler_types"
+	Test       fileType = "handler_test"
 	Model      fileType = "model"
+	Fields     fileType = "fields"
+	Injector   fileType = "injector"
+	Up         fileType = "migration_up"
+	Down       fileType = "migration_down"
+	AddUp      fileType = "migration_add_up"
+	AddDown    fileType = "migration_add_down"
 )
 
 const (
@@ -17,6 +25,20 @@ const (
 	RepositoryPath FilePath = _Internal + "/repository"
 	ModelPath      FilePath = _Internal + "/model"
 	ServicePath    FilePath = _Internal + "/service"
+	HandlerPath    FilePath = _Internal + "/handler/v1"
+	RouterPath     FilePath = _Internal + "/router"
+	DIPath         FilePath = _Internal + "/di"
+	MigrationPath  FilePath = _Internal + "/storage/migration/sql"
+)
+
+const (
+	TypesFile      = "types"
+	RouterFile     = "router.go"
+	RouterFunc     = "RegisterRouter"
+	RepositoryFile = "repository.go"
+	ServiceFile    = "service.go"
+	HandlerFile    = "handler.go"
+	WireFile       = "wire.go"
 )
 
 const (
@@ -24,6 +46,7 @@ const (
 	RouterSuffix  suffix = "_router"
 	ServiceSuffix suffix = "_service"
 	HandlerSuffix suffix = "_handler"
+	TestSuffix    suffix = "_handler_test"
 )
 
 type Make struct {
@@ -31,4 +54,13 @@ type Make struct {
 	FileType    fileType
 	SuffixFile  suffix
 	FeatureName string
+
+	// FileName replaces the snake cased feature name and suffix as the name of the generated file.
+	FileName string
+
+	// Ext is the extension of the generated file, ".go" when empty. Only Go files are formatted.
+	Ext string
+
+	// Fields are the columns of the generated resource.
+	Fields []Field
 }
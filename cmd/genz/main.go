@@ -1,9 +1,14 @@
 package main
 
 import (
+	"fmt"
+	"maps"
 	"os"
 
 	"github.com/geekswamp/zen/cmd/genz/internal/command/create"
+	"github.com/geekswamp/zen/cmd/genz/internal/command/keys"
+	"github.com/geekswamp/zen/cmd/genz/internal/command/project"
+	"github.com/geekswamp/zen/cmd/genz/internal/template"
 	"github.com/spf13/cobra"
 )
 
@@ -15,11 +20,31 @@ var mainCmd = &cobra.Command{
 }
 
 func init() {
-	mainCmd.AddCommand(create.CreateCmd)
+	mainCmd.AddCommand(create.CreateCmd, keys.KeysCmd, project.NewCmd)
 }
 
 func main() {
+	if err := loadConfig(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
 	if err := mainCmd.Execute(); err != nil {
 		os.Exit(1)
 	}
 }
+
+// loadConfig applies the config file of the project in the working directory, if any.
+func loadConfig() error {
+	config, err := template.LoadConfig(template.ConfigFile)
+	if err != nil {
+		return err
+	}
+
+	if config.TemplateDir != "" {
+		template.Dir = config.TemplateDir
+	}
+	maps.Copy(template.Vars, config.Vars)
+
+	return create.AddGenerators(config.Generators)
+}
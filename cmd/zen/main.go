@@ -1,12 +1,18 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/geekswamp/zen/configs"
 	"github.com/geekswamp/zen/internal/di"
+	"github.com/geekswamp/zen/internal/job"
+	"github.com/geekswamp/zen/internal/model"
 	"github.com/geekswamp/zen/internal/router"
+	"github.com/geekswamp/zen/internal/storage/migration"
 	"github.com/geekswamp/zen/internal/storage/seed"
 	"github.com/geekswamp/zen/pkg/http/middleware"
 	"github.com/geekswamp/zen/pkg/http/middleware/cors"
@@ -14,19 +20,57 @@ import (
 )
 
 func main() {
+	db := di.ProvidePostgres()
+
+	// Flags such as -env are parsed on init, so subcommands follow them: zen -env pro migrate up
+	if args := flag.Args(); len(args) > 0 {
+		var err error
+		switch args[0] {
+		case "migrate":
+			err = migrate(db, args[1:])
+		case "seed":
+			err = seedCommand(db, args[1:])
+		default:
+			err = fmt.Errorf("unknown command %q, use migrate or seed", args[0])
+		}
+
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	c := configs.Get()
 	s := server.New(
 		fmt.Sprintf("%s:%d", c.App.Host, c.App.Port),
 		server.SetMode(c.App.Mode),
-		server.Middlewares(cors.Default(), middleware.RequestID()),
+		server.Middlewares(cors.Default(), middleware.RequestID(), middleware.Timeout(c.App.RequestTimeout)),
 		server.ReadTimeout(30*time.Second),
 		server.WriteTimeout(30*time.Second),
 		server.RegisterRouter(router.RegisterRouter),
 	)
 
-	if err := seed.RunSeeders(di.ProvidePostgres()); err != nil {
-		panic(err)
+	if c.Postgres.MigrateOnStart {
+		m, err := migration.New(db)
+		if err != nil {
+			panic(err)
+		}
+
+		if _, err := m.Up(); err != nil {
+			panic(err)
+		}
+	}
+
+	if c.Postgres.SeedOnStart {
+		if err := seed.RunSeeders(db); err != nil {
+			panic(err)
+		}
 	}
 
+	go job.NewPurge(db, c.Purge.Retention, c.Purge.Interval,
+		&model.RefreshToken{}, &model.VerificationToken{}, &model.User{}, &model.Role{}, &model.Permission{},
+	).Run(context.Background())
+
 	s.Start()
 }
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/geekswamp/zen/internal/storage/migration"
+	"gorm.io/gorm"
+)
+
+var errMigrateUsage = errors.New("usage: zen migrate up|down [steps]|status|redo")
+
+// migrate runs the migrate subcommand with the arguments following it.
+func migrate(db *gorm.DB, args []string) error {
+	if len(args) == 0 {
+		return errMigrateUsage
+	}
+
+	m, err := migration.New(db)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		n, err := m.Up()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("applied %d migration(s)\n", n)
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("steps must be a positive number: %s", args[1])
+			}
+		}
+
+		n, err := m.Down(steps)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("reverted %d migration(s)\n", n)
+
+	case "redo":
+		return m.Redo()
+
+	case "status":
+		statuses, err := m.Status()
+		if err != nil {
+			return err
+		}
+		printStatus(statuses)
+
+	default:
+		return errMigrateUsage
+	}
+
+	return nil
+}
+
+func printStatus(statuses []migration.Status) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED")
+	for _, s := range statuses {
+		state, applied := "pending", "-"
+		if s.Applied {
+			state = "applied"
+			applied = time.UnixMilli(s.AppliedTime).Format(time.RFC3339)
+		}
+
+		switch {
+		case s.Missing:
+			state = "missing"
+		case s.Modified:
+			state = "modified"
+		}
+
+		fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", s.Version, s.Name, state, applied)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/geekswamp/zen/internal/storage/seed"
+	"gorm.io/gorm"
+)
+
+var errSeedUsage = errors.New("usage: zen seed run [name...]|rerun name...|status")
+
+// seedCommand runs the seed subcommand with the arguments following it.
+func seedCommand(db *gorm.DB, args []string) error {
+	if len(args) == 0 {
+		return seed.RunSeeders(db)
+	}
+
+	switch args[0] {
+	case "run":
+		return seed.Run(db, false, args[1:]...)
+
+	case "rerun":
+		if len(args) < 2 {
+			return errSeedUsage
+		}
+		return seed.Run(db, true, args[1:]...)
+
+	case "status":
+		statuses, err := seed.Statuses(db)
+		if err != nil {
+			return err
+		}
+		printSeedStatus(statuses)
+
+	default:
+		return errSeedUsage
+	}
+
+	return nil
+}
+
+func printSeedStatus(statuses []seed.Status) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, "NAME\tDEPENDS ON\tENVS\tRUNS\tLAST RUN")
+	for _, s := range statuses {
+		envs := "all"
+		if len(s.Envs) > 0 {
+			envs = strings.Join(s.Envs, ",")
+		}
+		if !s.Enabled {
+			envs += " (disabled)"
+		}
+
+		deps, lastRun := "-", "-"
+		if len(s.DependsOn) > 0 {
+			deps = strings.Join(s.DependsOn, ",")
+		}
+		if s.RunCount > 0 {
+			lastRun = time.UnixMilli(s.LastRunTime).Format(time.RFC3339)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", s.Name, deps, envs, s.RunCount, lastRun)
+	}
+}
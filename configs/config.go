@@ -31,6 +31,9 @@ type Config struct {
 		Mode string `mapstructure:"mode"` // debug or release
 		Host string `mapstructure:"host"`
 		Port uint32 `mapstructure:"port"`
+
+		// RequestTimeout cancels the database queries of requests running longer; 0 disables it.
+		RequestTimeout time.Duration `mapstructure:"request-timeout"`
 	} `mapstructure:"app"`
 
 	Postgres struct {
@@ -42,6 +45,13 @@ type Config struct {
 		SSLMode  string `mapstructure:"sslmode"`
 		Timezone string `mapstructure:"timezone"`
 
+		// MigrateOnStart applies pending migrations before the server starts.
+		MigrateOnStart bool `mapstructure:"migrate-on-start"`
+
+		// SeedOnStart runs the pending seeders before the server starts, after the migrations.
+		// Otherwise seeders only run with zen seed.
+		SeedOnStart bool `mapstructure:"seed-on-start"`
+
 		Base struct {
 			MaxOpenConn     int           `mapstructure:"max-open-conn"`
 			MaxIdleConn     int           `mapstructure:"max-idle-conn"`
@@ -63,9 +73,56 @@ type Config struct {
 	} `mapstructure:"password"`
 
 	JWT struct {
-		PubKeyPath  string `mapstructure:"pub-key-path"`
-		PrivKeyPath string `mapstructure:"priv-key-path"`
+		Algorithm       string        `mapstructure:"algorithm"` // RS256, ES256, ES384 or EdDSA
+		KeyID           string        `mapstructure:"kid"`       // defaults to the key thumbprint
+		PubKeyPath      string        `mapstructure:"pub-key-path"`
+		PrivKeyPath     string        `mapstructure:"priv-key-path"`
+		Issuer          string        `mapstructure:"issuer"`
+		Audience        []string      `mapstructure:"audience"`
+		AccessTokenTTL  time.Duration `mapstructure:"access-token-ttl"`
+		RefreshTokenTTL time.Duration `mapstructure:"refresh-token-ttl"`
+
+		RevocationCacheTTL time.Duration `mapstructure:"revocation-cache-ttl"`
+
+		RetiredKeys []struct {
+			KeyID      string `mapstructure:"kid"`
+			Algorithm  string `mapstructure:"algorithm"`
+			PubKeyPath string `mapstructure:"pub-key-path"`
+			Until      string `mapstructure:"until"` // RFC 3339
+		} `mapstructure:"retired-keys"`
 	} `mapstructure:"jwt"`
+
+	Mail struct {
+		Driver string `mapstructure:"driver"` // smtp or log
+		From   string `mapstructure:"from"`
+		Dir    string `mapstructure:"dir"` // log driver only, messages are logged when empty
+
+		SMTP struct {
+			Host     string `mapstructure:"host"`
+			Port     uint32 `mapstructure:"port"`
+			Username string `mapstructure:"username"`
+			Password string `mapstructure:"password"`
+		} `mapstructure:"smtp"`
+	} `mapstructure:"mail"`
+
+	Verification struct {
+		TokenTTL time.Duration `mapstructure:"token-ttl"`
+		URL      string        `mapstructure:"url"`
+	} `mapstructure:"verification"`
+
+	PasswordReset struct {
+		TokenTTL time.Duration `mapstructure:"token-ttl"`
+		URL      string        `mapstructure:"url"`
+	} `mapstructure:"password-reset"`
+
+	Purge struct {
+		Retention time.Duration `mapstructure:"retention"` // 0 disables purging
+		Interval  time.Duration `mapstructure:"interval"`
+	} `mapstructure:"purge"`
+
+	Pagination struct {
+		CursorSecret string `mapstructure:"cursor-secret"` // signs keyset pagination cursors
+	} `mapstructure:"pagination"`
 }
 
 func init() {
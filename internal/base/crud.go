@@ -0,0 +1,200 @@
+package base
+
+import (
+	"context"
+	"maps"
+
+	"github.com/geekswamp/zen/internal/errors"
+	"github.com/geekswamp/zen/internal/http"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Scope narrows down the rows a CRUD query operates on, e.g. a filter built with Where.
+type Scope = func(db *gorm.DB) *gorm.DB
+
+// Where returns a scope adding the given condition to a query.
+func Where(query any, args ...any) Scope {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(query, args...)
+	}
+}
+
+// CRUD implements the common operations of a repository for the model T, which must
+// embed Model. Feature repositories embed it and only add their custom queries.
+//
+// Soft deleted rows are skipped unless the WithDeleted or OnlyDeleted scope is passed.
+// Lookups and writes of a single row return gorm.ErrRecordNotFound when no row
+// matches, and writes return gorm.ErrDuplicatedKey on unique constraint violations.
+type CRUD[T any] struct {
+	repo Repository
+}
+
+// NewCRUD creates a CRUD for the model T on top of the given repository.
+func NewCRUD[T any](repo Repository) CRUD[T] {
+	return CRUD[T]{repo: repo}
+}
+
+// Create inserts the entity and fills in its generated fields.
+func (c CRUD[T]) Create(ctx context.Context, entity *T) error {
+	err := c.repo.DB(ctx).Create(entity).Error
+	if err := c.repo.IsDuplicateKey(err); err != nil {
+		return err
+	}
+
+	return err
+}
+
+func (c CRUD[T]) FindByID(ctx context.Context, id uuid.UUID) (*T, error) {
+	return c.FindOne(ctx, Where("id = ?", id))
+}
+
+// FindOne returns the first row matching the scopes.
+func (c CRUD[T]) FindOne(ctx context.Context, scopes ...Scope) (*T, error) {
+	entity := new(T)
+	if err := c.repo.DB(ctx).Scopes(scopes...).First(entity).Error; err != nil {
+		return nil, err
+	}
+
+	return entity, nil
+}
+
+// List returns a page of the rows matching the scopes, ordered by creation time in the
+// direction of the pagination sort option (newest first by default), and the total
+// number of matching rows. Pagination.Search is not applied, pass a scope for it.
+func (c CRUD[T]) List(ctx context.Context, p *http.Pagination, scopes ...Scope) ([]T, int64, error) {
+	total, err := c.Count(ctx, scopes...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	desc := p.GetSort() != http.Asc
+	entities := []T{}
+	err = c.repo.DB(ctx).Scopes(scopes...).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: "created_time"}, Desc: desc}).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: "id"}, Desc: desc}).
+		Offset(int(p.GetOffset())).
+		Limit(int(p.GetLimit())).
+		Find(&entities).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return entities, total, nil
+}
+
+// AnyVersion disables the version check of UpdateVersion, SoftDelete and Restore.
+const AnyVersion int64 = 0
+
+// Update sets the columns of the given map on the row with the given id and bumps its version.
+func (c CRUD[T]) Update(ctx context.Context, id uuid.UUID, updateMap UpdateMap) error {
+	return c.UpdateVersion(ctx, id, AnyVersion, updateMap)
+}
+
+// UpdateVersion is like Update but only updates the row while it still has the given version,
+// otherwise errors.ErrVersionConflict is returned. AnyVersion skips the check.
+func (c CRUD[T]) UpdateVersion(ctx context.Context, id uuid.UUID, version int64, updateMap UpdateMap) error {
+	values := maps.Clone(updateMap)
+	values["version"] = gorm.Expr("version + 1")
+
+	tx := c.repo.DB(ctx).Model(new(T)).Where("id = ?", id).Scopes(withVersion(version))
+
+	return c.checkVersion(ctx, id, version, c.updateOne(tx.Updates(values)))
+}
+
+// SoftDelete marks the row as deleted by setting its deleted time, while it still has the
+// given version, otherwise errors.ErrVersionConflict is returned. AnyVersion skips the check.
+func (c CRUD[T]) SoftDelete(ctx context.Context, id uuid.UUID, version int64) error {
+	tx := c.repo.DB(ctx).Where("id = ?", id).Scopes(withVersion(version))
+
+	return c.checkVersion(ctx, id, version, c.updateOne(tx.Delete(new(T))))
+}
+
+// Restore clears the deleted time of a soft deleted row and bumps its version, while it still
+// has the given version, otherwise errors.ErrVersionConflict is returned. AnyVersion skips the check.
+func (c CRUD[T]) Restore(ctx context.Context, id uuid.UUID, version int64) error {
+	tx := c.repo.DB(ctx).Model(new(T)).Scopes(OnlyDeleted).Where("id = ?", id).Scopes(withVersion(version))
+	err := c.updateOne(tx.Updates(UpdateMap{"deleted_time": nil, "version": gorm.Expr("version + 1")}))
+
+	return c.checkVersion(ctx, id, version, err, OnlyDeleted)
+}
+
+// checkVersion turns gorm.ErrRecordNotFound of a statement guarded by the version into
+// errors.ErrVersionConflict when the row matching the scopes still exists.
+func (c CRUD[T]) checkVersion(ctx context.Context, id uuid.UUID, version int64, err error, scopes ...Scope) error {
+	if err != gorm.ErrRecordNotFound || version == AnyVersion {
+		return err
+	}
+
+	exists, existsErr := c.Exists(ctx, append(scopes, Where("id = ?", id))...)
+	if existsErr != nil {
+		return existsErr
+	}
+
+	if exists {
+		return errors.ErrVersionConflict
+	}
+
+	return err
+}
+
+func withVersion(version int64) Scope {
+	return func(db *gorm.DB) *gorm.DB {
+		if version == AnyVersion {
+			return db
+		}
+
+		return db.Where("version = ?", version)
+	}
+}
+
+// HardDelete removes the row from the table.
+func (c CRUD[T]) HardDelete(ctx context.Context, id uuid.UUID) error {
+	qr := c.repo.DB(ctx).Unscoped().Where("id = ?", id).Delete(new(T))
+	if qr.Error != nil {
+		return qr.Error
+	}
+
+	if qr.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (c CRUD[T]) Count(ctx context.Context, scopes ...Scope) (int64, error) {
+	var count int64
+	if err := c.repo.DB(ctx).Model(new(T)).Scopes(scopes...).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Exists reports whether at least one row matches the scopes.
+func (c CRUD[T]) Exists(ctx context.Context, scopes ...Scope) (bool, error) {
+	var exists bool
+	err := c.repo.DB(ctx).Model(new(T)).Select("1").Scopes(scopes...).Limit(1).Find(&exists).Error
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (c CRUD[T]) updateOne(qr *gorm.DB) error {
+	if err := c.repo.IsDuplicateKey(qr.Error); err != nil {
+		return err
+	}
+
+	if qr.Error != nil {
+		return qr.Error
+	}
+
+	if qr.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,188 @@
+package base_test
+
+import (
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/geekswamp/zen/internal/base"
+	errs "github.com/geekswamp/zen/internal/errors"
+	"github.com/geekswamp/zen/internal/http"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func newMockCRUD(t *testing.T) (base.CRUD[entity], sqlmock.Sqlmock) {
+	conn, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{SkipDefaultTransaction: true})
+	require.NoError(t, err)
+
+	return base.NewCRUD[entity](base.NewRepo(db)), sqlMock
+}
+
+func TestUpdateVersion(t *testing.T) {
+	id := uuid.New()
+	update := regexp.QuoteMeta(`UPDATE "entities" SET "name"=$1,"version"=version + 1,"updated_time"=$2 WHERE id = $3 AND version = $4 AND "entities"."deleted_time" IS NULL`)
+	exists := regexp.QuoteMeta(`SELECT 1 FROM "entities" WHERE id = $1 AND "entities"."deleted_time" IS NULL LIMIT $2`)
+
+	testCases := []struct {
+		name    string
+		version int64
+		expect  func(m sqlmock.Sqlmock)
+		wantErr error
+	}{
+		{
+			name:    "Updated",
+			version: 3,
+			expect: func(m sqlmock.Sqlmock) {
+				m.ExpectExec(update).WithArgs("new", sqlmock.AnyArg(), id, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:    "Stale Version",
+			version: 3,
+			expect: func(m sqlmock.Sqlmock) {
+				m.ExpectExec(update).WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectQuery(exists).WithArgs(id, 1).WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(1))
+			},
+			wantErr: errs.ErrVersionConflict,
+		},
+		{
+			name:    "Missing Row",
+			version: 3,
+			expect: func(m sqlmock.Sqlmock) {
+				m.ExpectExec(update).WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectQuery(exists).WithArgs(id, 1).WillReturnRows(sqlmock.NewRows([]string{"?column?"}))
+			},
+			wantErr: gorm.ErrRecordNotFound,
+		},
+		{
+			name:    "Missing Row Of Any Version",
+			version: base.AnyVersion,
+			expect: func(m sqlmock.Sqlmock) {
+				m.ExpectExec(regexp.QuoteMeta(`UPDATE "entities" SET "name"=$1,"version"=version + 1,"updated_time"=$2 WHERE id = $3 AND "entities"."deleted_time" IS NULL`)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: gorm.ErrRecordNotFound,
+		},
+		{
+			name:    "Duplicate Key",
+			version: 3,
+			expect: func(m sqlmock.Sqlmock) {
+				m.ExpectExec(update).WillReturnError(errors.New(`ERROR: duplicate key value violates unique constraint "idx_entities_name" (SQLSTATE 23505)`))
+			},
+			wantErr: gorm.ErrDuplicatedKey,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			crud, sqlMock := newMockCRUD(t)
+			tc.expect(sqlMock)
+
+			err := crud.UpdateVersion(t.Context(), id, tc.version, base.UpdateMap{"name": "new"})
+			assert.Equal(t, tc.wantErr, err)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRestore(t *testing.T) {
+	id := uuid.New()
+	restore := regexp.QuoteMeta(`UPDATE "entities" SET "deleted_time"=$1,"version"=version + 1,"updated_time"=$2 WHERE id = $3 AND "entities"."deleted_time" IS NOT NULL AND version = $4`)
+	exists := regexp.QuoteMeta(`SELECT 1 FROM "entities" WHERE "entities"."deleted_time" IS NOT NULL AND id = $1 LIMIT $2`)
+
+	testCases := []struct {
+		name    string
+		expect  func(m sqlmock.Sqlmock)
+		wantErr error
+	}{
+		{
+			name: "Restored",
+			expect: func(m sqlmock.Sqlmock) {
+				m.ExpectExec(restore).WithArgs(nil, sqlmock.AnyArg(), id, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Stale Version",
+			expect: func(m sqlmock.Sqlmock) {
+				m.ExpectExec(restore).WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectQuery(exists).WithArgs(id, 1).WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(1))
+			},
+			wantErr: errs.ErrVersionConflict,
+		},
+		{
+			name: "Not Deleted",
+			expect: func(m sqlmock.Sqlmock) {
+				m.ExpectExec(restore).WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectQuery(exists).WithArgs(id, 1).WillReturnRows(sqlmock.NewRows([]string{"?column?"}))
+			},
+			wantErr: gorm.ErrRecordNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			crud, sqlMock := newMockCRUD(t)
+			tc.expect(sqlMock)
+
+			err := crud.Restore(t.Context(), id, 2)
+			assert.Equal(t, tc.wantErr, err)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	testCases := []struct {
+		name       string
+		pagination http.Pagination
+		wantOrder  string
+		wantArgs   []driver.Value
+	}{
+		{
+			name:       "Newest First By Default",
+			pagination: http.Pagination{},
+			wantOrder:  `ORDER BY "created_time" DESC,"id" DESC LIMIT $2`,
+			wantArgs:   []driver.Value{"a", 10},
+		},
+		{
+			name:       "Oldest First",
+			pagination: http.Pagination{Sort: http.Asc},
+			wantOrder:  `ORDER BY "created_time","id" LIMIT $2`,
+			wantArgs:   []driver.Value{"a", 10},
+		},
+		{
+			name:       "Third Page",
+			pagination: http.Pagination{Page: 3, Limit: 5, Sort: http.Desc},
+			wantOrder:  `ORDER BY "created_time" DESC,"id" DESC LIMIT $2 OFFSET $3`,
+			wantArgs:   []driver.Value{"a", 5, 10},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			crud, sqlMock := newMockCRUD(t)
+			sqlMock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "entities" WHERE name = $1 AND "entities"."deleted_time" IS NULL`)).
+				WithArgs("a").
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+			sqlMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "entities" WHERE name = $1 AND "entities"."deleted_time" IS NULL ` + tc.wantOrder)).
+				WithArgs(tc.wantArgs...).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(uuid.New(), "a").AddRow(uuid.New(), "a"))
+
+			entities, total, err := crud.List(t.Context(), &tc.pagination, base.Where("name = ?", "a"))
+			require.NoError(t, err)
+			assert.Len(t, entities, 2)
+			assert.Equal(t, int64(12), total)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
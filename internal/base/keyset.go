@@ -0,0 +1,94 @@
+package base
+
+import (
+	"slices"
+
+	"github.com/geekswamp/zen/internal/http"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Keyed is implemented by entities that can be paginated with Keyset.
+// Every model embedding Model implements it.
+type Keyed interface {
+	KeysetKey() (createdTime int64, id uuid.UUID)
+}
+
+// KeysetKey returns the (created_time, id) pair that orders the model in keyset pagination.
+func (m Model) KeysetKey() (int64, uuid.UUID) {
+	return m.CreatedTime, m.ID
+}
+
+// KeysetResult holds a page of rows and the cursors of its neighbouring pages.
+// A nil cursor means there is no page in that direction.
+type KeysetResult[T Keyed] struct {
+	Rows []T
+	Next *http.Cursor
+	Prev *http.Cursor
+}
+
+// Keyset returns a scope that selects the page on the side of the cursor given by its
+// direction, newest rows first. A nil cursor selects the first page. The columns are
+// not qualified, so queries joining other tables with the same columns must not use it.
+// One row more than limit is fetched so that KeysetPage can tell whether more rows follow.
+func Keyset(cursor *http.Cursor, limit int64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		order := "created_time DESC, id DESC"
+
+		if cursor != nil {
+			switch cursor.Direction {
+			case http.Prev:
+				db = db.Where("(created_time, id) > (?, ?)", cursor.CreatedTime, cursor.ID)
+				order = "created_time ASC, id ASC"
+			default:
+				db = db.Where("(created_time, id) < (?, ?)", cursor.CreatedTime, cursor.ID)
+			}
+		}
+
+		return db.Order(order).Limit(int(limit) + 1)
+	}
+}
+
+// KeysetPage trims rows fetched with the Keyset scope to the page size, restores the
+// newest-first order and computes the cursors of the neighbouring pages.
+func KeysetPage[T Keyed](rows []T, cursor *http.Cursor, limit int64) KeysetResult[T] {
+	hasMore := int64(len(rows)) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+
+	backward := cursor != nil && cursor.Direction == http.Prev
+	if backward {
+		slices.Reverse(rows)
+	}
+
+	result := KeysetResult[T]{Rows: rows}
+	if len(rows) == 0 {
+		return result
+	}
+
+	// A page reached by going backward always has a page after it, and one reached
+	// by going forward from a cursor always has a page before it.
+	if hasMore || backward {
+		createdTime, id := rows[len(rows)-1].KeysetKey()
+		result.Next = &http.Cursor{CreatedTime: createdTime, ID: id, Direction: http.Next}
+	}
+
+	if (hasMore && backward) || (!backward && cursor != nil) {
+		createdTime, id := rows[0].KeysetKey()
+		result.Prev = &http.Cursor{CreatedTime: createdTime, ID: id, Direction: http.Prev}
+	}
+
+	return result
+}
+
+// FindKeyset loads the page of rows selected by the cursor with the given query.
+func FindKeyset[T Keyed](db *gorm.DB, cursor *http.Cursor, limit int64) (*KeysetResult[T], error) {
+	rows := []T{}
+	if err := db.Scopes(Keyset(cursor, limit)).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	result := KeysetPage(rows, cursor, limit)
+	return &result, nil
+}
@@ -0,0 +1,102 @@
+package base_test
+
+import (
+	"testing"
+
+	"github.com/geekswamp/zen/internal/base"
+	"github.com/geekswamp/zen/internal/http"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type row struct {
+	base.Model
+}
+
+func newRows(createdTimes ...int64) []row {
+	rows := make([]row, 0, len(createdTimes))
+	for _, t := range createdTimes {
+		rows = append(rows, row{Model: base.Model{ID: uuid.New(), CreatedTime: t}})
+	}
+	return rows
+}
+
+func createdTimes(rows []row) []int64 {
+	times := make([]int64, 0, len(rows))
+	for _, r := range rows {
+		times = append(times, r.CreatedTime)
+	}
+	return times
+}
+
+func TestKeysetPage(t *testing.T) {
+	testCases := []struct {
+		name      string
+		rows      []row
+		cursor    *http.Cursor
+		wantTimes []int64
+		wantNext  bool
+		wantPrev  bool
+	}{
+		{
+			name:      "First Page With More",
+			rows:      newRows(5, 4, 3),
+			cursor:    nil,
+			wantTimes: []int64{5, 4},
+			wantNext:  true,
+			wantPrev:  false,
+		},
+		{
+			name:      "Only Page",
+			rows:      newRows(5, 4),
+			cursor:    nil,
+			wantTimes: []int64{5, 4},
+			wantNext:  false,
+			wantPrev:  false,
+		},
+		{
+			name:      "Last Page Going Forward",
+			rows:      newRows(3),
+			cursor:    &http.Cursor{CreatedTime: 4, Direction: http.Next},
+			wantTimes: []int64{3},
+			wantNext:  false,
+			wantPrev:  true,
+		},
+		{
+			name:      "Middle Page Going Backward",
+			rows:      newRows(3, 4, 5),
+			cursor:    &http.Cursor{CreatedTime: 2, Direction: http.Prev},
+			wantTimes: []int64{4, 3},
+			wantNext:  true,
+			wantPrev:  true,
+		},
+		{
+			name:      "First Page Going Backward",
+			rows:      newRows(3, 4),
+			cursor:    &http.Cursor{CreatedTime: 2, Direction: http.Prev},
+			wantTimes: []int64{4, 3},
+			wantNext:  true,
+			wantPrev:  false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := base.KeysetPage(tc.rows, tc.cursor, 2)
+
+			assert.Equal(t, tc.wantTimes, createdTimes(result.Rows))
+			assert.Equal(t, tc.wantNext, result.Next != nil)
+			assert.Equal(t, tc.wantPrev, result.Prev != nil)
+
+			if result.Next != nil {
+				assert.Equal(t, http.Next, result.Next.Direction)
+				assert.Equal(t, tc.wantTimes[len(tc.wantTimes)-1], result.Next.CreatedTime)
+			}
+
+			if result.Prev != nil {
+				assert.Equal(t, http.Prev, result.Prev.Direction)
+				assert.Equal(t, tc.wantTimes[0], result.Prev.CreatedTime)
+			}
+		})
+	}
+}
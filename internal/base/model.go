@@ -4,8 +4,9 @@ import "github.com/google/uuid"
 
 // Model represents a base model structure with common fields used across entities.
 type Model struct {
-	ID          uuid.UUID `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
-	CreatedTime int64     `gorm:"column:created_time;autoCreateTime:milli"`
-	UpdatedTime int64     `gorm:"column:updated_time;autoUpdateTime:milli"`
-	DeletedTime *int64    `gorm:"column:deleted_time;index"`
+	ID          uuid.UUID   `gorm:"primaryKey;type:uuid;default:uuid_generate_v4();index:,composite:keyset,priority:2"`
+	CreatedTime int64       `gorm:"column:created_time;autoCreateTime:milli;index:,composite:keyset,priority:1"`
+	UpdatedTime int64       `gorm:"column:updated_time;autoUpdateTime:milli"`
+	DeletedTime DeletedTime `gorm:"column:deleted_time;index"`
+	Version     int64       `gorm:"column:version;not null;default:1"`
 }
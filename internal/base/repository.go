@@ -1,6 +1,7 @@
 package base
 
 import (
+	"context"
 	"strings"
 
 	"gorm.io/gorm"
@@ -22,9 +23,14 @@ func NewRepo(db *gorm.DB) Repository {
 	return Repository{db: db}
 }
 
-// DB returns the GORM database instance used by the repository.
-func (r Repository) DB() *gorm.DB {
-	return r.db
+// DB returns the GORM database to query with the given context: the transaction of
+// Transactor.WithinTx when the context carries one, otherwise the connection pool.
+func (r Repository) DB(ctx context.Context) *gorm.DB {
+	if tx := txFrom(ctx); tx != nil {
+		return tx.WithContext(ctx)
+	}
+
+	return r.db.WithContext(ctx)
 }
 
 // IsDuplicateKey checks if the given error is a duplicate key constraint violation in the database.
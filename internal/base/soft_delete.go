@@ -0,0 +1,186 @@
+package base
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// DeletedTime marks a row as soft deleted with the time of deletion in Unix milliseconds.
+// It works like gorm.DeletedAt: queries, updates and deletes on a model with this field
+// skip soft deleted rows, and Delete sets the field instead of removing the row.
+// Use the WithDeleted and OnlyDeleted scopes, or Unscoped, to reach deleted rows.
+type DeletedTime sql.NullInt64
+
+// Scan implements the Scanner interface.
+func (d *DeletedTime) Scan(value any) error {
+	return (*sql.NullInt64)(d).Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (d DeletedTime) Value() (driver.Value, error) {
+	if !d.Valid {
+		return nil, nil
+	}
+
+	return d.Int64, nil
+}
+
+// Millis returns the deletion time, or nil when the row is not deleted.
+func (d DeletedTime) Millis() *int64 {
+	if !d.Valid {
+		return nil
+	}
+
+	return &d.Int64
+}
+
+func (d DeletedTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Millis())
+}
+
+func (d *DeletedTime) UnmarshalJSON(b []byte) error {
+	var millis *int64
+	if err := json.Unmarshal(b, &millis); err != nil {
+		return err
+	}
+
+	d.Valid = millis != nil
+	if d.Valid {
+		d.Int64 = *millis
+	}
+
+	return nil
+}
+
+// WithDeleted is a scope that includes soft deleted rows in a query.
+func WithDeleted(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
+
+// OnlyDeleted is a scope that restricts a query to soft deleted rows.
+func OnlyDeleted(db *gorm.DB) *gorm.DB {
+	return db.Unscoped().Where(clause.Neq{Column: clause.Column{Table: clause.CurrentTable, Name: "deleted_time"}, Value: nil})
+}
+
+func (DeletedTime) QueryClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{softDeleteQueryClause{field: f}}
+}
+
+func (DeletedTime) UpdateClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{softDeleteUpdateClause{field: f}}
+}
+
+func (DeletedTime) DeleteClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{softDeleteDeleteClause{field: f}}
+}
+
+type softDeleteQueryClause struct {
+	field *schema.Field
+}
+
+func (sd softDeleteQueryClause) Name() string {
+	return ""
+}
+
+func (sd softDeleteQueryClause) Build(clause.Builder) {
+}
+
+func (sd softDeleteQueryClause) MergeClause(*clause.Clause) {
+}
+
+func (sd softDeleteQueryClause) ModifyStatement(stmt *gorm.Statement) {
+	if _, ok := stmt.Clauses["soft_delete_enabled"]; ok || stmt.Unscoped {
+		return
+	}
+
+	// A lone OR condition would otherwise swallow the soft delete condition.
+	if c, ok := stmt.Clauses["WHERE"]; ok {
+		if where, ok := c.Expression.(clause.Where); ok && len(where.Exprs) >= 1 {
+			for _, expr := range where.Exprs {
+				if orCond, ok := expr.(clause.OrConditions); ok && len(orCond.Exprs) == 1 {
+					where.Exprs = []clause.Expression{clause.And(where.Exprs...)}
+					c.Expression = where
+					stmt.Clauses["WHERE"] = c
+					break
+				}
+			}
+		}
+	}
+
+	stmt.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: sd.field.DBName}, Value: nil},
+	}})
+	stmt.Clauses["soft_delete_enabled"] = clause.Clause{}
+}
+
+type softDeleteUpdateClause struct {
+	field *schema.Field
+}
+
+func (sd softDeleteUpdateClause) Name() string {
+	return ""
+}
+
+func (sd softDeleteUpdateClause) Build(clause.Builder) {
+}
+
+func (sd softDeleteUpdateClause) MergeClause(*clause.Clause) {
+}
+
+func (sd softDeleteUpdateClause) ModifyStatement(stmt *gorm.Statement) {
+	if stmt.SQL.Len() == 0 && !stmt.Unscoped {
+		softDeleteQueryClause(sd).ModifyStatement(stmt)
+	}
+}
+
+type softDeleteDeleteClause struct {
+	field *schema.Field
+}
+
+func (sd softDeleteDeleteClause) Name() string {
+	return ""
+}
+
+func (sd softDeleteDeleteClause) Build(clause.Builder) {
+}
+
+func (sd softDeleteDeleteClause) MergeClause(*clause.Clause) {
+}
+
+func (sd softDeleteDeleteClause) ModifyStatement(stmt *gorm.Statement) {
+	if stmt.SQL.Len() > 0 || stmt.Unscoped {
+		return
+	}
+
+	millis := stmt.DB.NowFunc().UnixMilli()
+	stmt.AddClause(clause.Set{{Column: clause.Column{Name: sd.field.DBName}, Value: millis}})
+	stmt.SetColumn(sd.field.DBName, millis, true)
+
+	if stmt.Schema != nil {
+		_, queryValues := schema.GetIdentityFieldValuesMap(stmt.Context, stmt.ReflectValue, stmt.Schema.PrimaryFields)
+		column, values := schema.ToQueryValues(stmt.Table, stmt.Schema.PrimaryFieldDBNames, queryValues)
+
+		if len(values) > 0 {
+			stmt.AddClause(clause.Where{Exprs: []clause.Expression{clause.IN{Column: column, Values: values}}})
+		}
+
+		if stmt.ReflectValue.CanAddr() && stmt.Dest != stmt.Model && stmt.Model != nil {
+			_, queryValues = schema.GetIdentityFieldValuesMap(stmt.Context, reflect.ValueOf(stmt.Model), stmt.Schema.PrimaryFields)
+			column, values = schema.ToQueryValues(stmt.Table, stmt.Schema.PrimaryFieldDBNames, queryValues)
+
+			if len(values) > 0 {
+				stmt.AddClause(clause.Where{Exprs: []clause.Expression{clause.IN{Column: column, Values: values}}})
+			}
+		}
+	}
+
+	softDeleteQueryClause(sd).ModifyStatement(stmt)
+	stmt.AddClauseIfNotExists(clause.Update{})
+	stmt.Build(stmt.DB.Callback().Update().Clauses...)
+}
@@ -0,0 +1,73 @@
+package base_test
+
+import (
+	"testing"
+
+	"github.com/geekswamp/zen/internal/base"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type entity struct {
+	base.Model
+	Name string
+}
+
+func newDryRunDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	require.NoError(t, err)
+	return db
+}
+
+func TestSoftDelete(t *testing.T) {
+	db := newDryRunDB(t)
+	id := uuid.New()
+
+	testCases := []struct {
+		name    string
+		query   func(db *gorm.DB) *gorm.DB
+		wantSQL string
+	}{
+		{
+			name:    "Query Skips Deleted",
+			query:   func(db *gorm.DB) *gorm.DB { return db.Where("name = ?", "a").Find(&[]entity{}) },
+			wantSQL: `SELECT * FROM "entities" WHERE name = $1 AND "entities"."deleted_time" IS NULL`,
+		},
+		{
+			name:    "Query With Deleted",
+			query:   func(db *gorm.DB) *gorm.DB { return db.Scopes(base.WithDeleted).Find(&[]entity{}) },
+			wantSQL: `SELECT * FROM "entities"`,
+		},
+		{
+			name:    "Query Only Deleted",
+			query:   func(db *gorm.DB) *gorm.DB { return db.Scopes(base.OnlyDeleted).Find(&[]entity{}) },
+			wantSQL: `SELECT * FROM "entities" WHERE "entities"."deleted_time" IS NOT NULL`,
+		},
+		{
+			name:    "Delete Sets Deleted Time",
+			query:   func(db *gorm.DB) *gorm.DB { return db.Where("id = ?", id).Delete(&entity{}) },
+			wantSQL: `UPDATE "entities" SET "deleted_time"=$1 WHERE id = $2 AND "entities"."deleted_time" IS NULL`,
+		},
+		{
+			name:    "Unscoped Delete Removes Row",
+			query:   func(db *gorm.DB) *gorm.DB { return db.Unscoped().Where("id = ?", id).Delete(&entity{}) },
+			wantSQL: `DELETE FROM "entities" WHERE id = $1`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tx := tc.query(db.Session(&gorm.Session{}))
+			require.NoError(t, tx.Error)
+			stmt := tx.Statement
+			assert.Equal(t, tc.wantSQL, stmt.SQL.String())
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package base
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// _TxRetries is how many times WithinTx runs a transaction again after a serialization failure.
+const _TxRetries = 3
+
+// retryableStates are the SQLSTATE codes of serialization failures and deadlocks, after
+// which the whole transaction may succeed when run again.
+var retryableStates = []string{"40001", "40P01"}
+
+type txKey struct{}
+
+// Transactor runs units of work spanning several repository calls in one transaction.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type GormTransactor struct {
+	db *gorm.DB
+}
+
+// NewTransactor creates a Transactor starting its transactions on the given connection.
+func NewTransactor(db *gorm.DB) Transactor {
+	return GormTransactor{db: db}
+}
+
+// WithinTx runs fn in a transaction and passes it a context carrying the transaction,
+// which Repository.DB picks up. The transaction is committed when fn returns nil and
+// rolled back otherwise.
+//
+// Nested calls run in a savepoint of the outer transaction, so their error only rolls
+// back their own work. The outermost call runs fn again when the transaction fails with
+// a serialization failure or deadlock, so fn must be safe to run more than once.
+func (t GormTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if tx := txFrom(ctx); tx != nil {
+		return tx.Transaction(func(savepoint *gorm.DB) error {
+			return fn(context.WithValue(ctx, txKey{}, savepoint))
+		})
+	}
+
+	var err error
+	for attempt := range _TxRetries + 1 {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(attempt*attempt) * 10 * time.Millisecond):
+			}
+		}
+
+		err = t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return fn(context.WithValue(ctx, txKey{}, tx))
+		})
+		if !IsRetryable(err) {
+			return err
+		}
+	}
+
+	return err
+}
+
+// IsRetryable reports whether the error is a serialization failure or deadlock reported by Postgres.
+func IsRetryable(err error) bool {
+	var state interface{ SQLState() string }
+	return errors.As(err, &state) && slices.Contains(retryableStates, state.SQLState())
+}
+
+func txFrom(ctx context.Context) *gorm.DB {
+	tx, _ := ctx.Value(txKey{}).(*gorm.DB)
+	return tx
+}
@@ -0,0 +1,32 @@
+package base_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/geekswamp/zen/internal/base"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRetryable(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "nil", err: nil, expected: false},
+		{name: "plain error", err: errors.New("boom"), expected: false},
+		{name: "serialization failure", err: &pgconn.PgError{Code: "40001"}, expected: true},
+		{name: "deadlock", err: &pgconn.PgError{Code: "40P01"}, expected: true},
+		{name: "wrapped", err: fmt.Errorf("update user: %w", &pgconn.PgError{Code: "40001"}), expected: true},
+		{name: "unique violation", err: &pgconn.PgError{Code: "23505"}, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, base.IsRetryable(tc.err))
+		})
+	}
+}
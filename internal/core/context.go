@@ -1,6 +1,8 @@
 package core
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -10,6 +12,9 @@ const (
 	_UserSessionKey string = "__user_session__"
 )
 
+// contextKey keys the request-scoped data copied into the context.Context of the HTTP request.
+type contextKey string
+
 // Context holds request-scoped data for use throughout the application.
 type Context struct {
 	ctx *gin.Context
@@ -20,9 +25,10 @@ func NewContext(ctx *gin.Context) Context {
 	return Context{ctx: ctx}
 }
 
-// SetRequestID store the request id in the context.
+// SetRequestID store the request id in the context and in the context of the HTTP request.
 func (c *Context) SetRequestID(id uuid.UUID) {
 	c.ctx.Set(_RequestIDKey, id.String())
+	c.setRequestValue(_RequestIDKey, id.String())
 }
 
 // GetRequestID generates and returns a new UUID as a string to uniquely identify a request.
@@ -39,9 +45,10 @@ func (c *Context) GetRequestID() *string {
 	return nil
 }
 
-// SetUserSession store the user session in the context.
+// SetUserSession store the user session in the context and in the context of the HTTP request.
 func (c *Context) SetUserSession(u UserSession) {
 	c.ctx.Set(_UserSessionKey, u)
+	c.setRequestValue(_UserSessionKey, u)
 }
 
 // GetUserSession retrieves the user session from the context.
@@ -67,3 +74,28 @@ func (c *Context) ParseIDParam() (ID uuid.UUID, err error) {
 
 	return ID, nil
 }
+
+// setRequestValue copies a value into the context.Context of the HTTP request, so that it
+// reaches the layers below the handler through ctx.Request.Context().
+func (c *Context) setRequestValue(key string, value any) {
+	if c.ctx.Request == nil {
+		return
+	}
+
+	c.ctx.Request = c.ctx.Request.WithContext(context.WithValue(c.ctx.Request.Context(), contextKey(key), value))
+}
+
+// RequestIDFrom returns the request id stored by SetRequestID, or an empty string.
+func RequestIDFrom(ctx context.Context) string {
+	requestID, _ := ctx.Value(contextKey(_RequestIDKey)).(string)
+	return requestID
+}
+
+// UserSessionFrom returns the user session stored by SetUserSession, or nil.
+func UserSessionFrom(ctx context.Context) *UserSession {
+	if u, ok := ctx.Value(contextKey(_UserSessionKey)).(UserSession); ok {
+		return &u
+	}
+
+	return nil
+}
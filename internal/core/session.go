@@ -1,7 +1,23 @@
 package core
 
-import "github.com/google/uuid"
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type UserSession struct {
-	ID uuid.UUID `json:"id"`
+	ID          uuid.UUID      `json:"id"`
+	SessionID   string         `json:"session_id"`
+	TokenID     string         `json:"token_id"`
+	ExpiresAt   time.Time      `json:"expires_at"`
+	Roles       []string       `json:"roles,omitempty"`
+	Permissions []string       `json:"permissions,omitempty"`
+	Extra       map[string]any `json:"extra,omitempty"`
+}
+
+// HasPermission reports whether the session has been granted the given permission.
+func (u UserSession) HasPermission(permission string) bool {
+	return slices.Contains(u.Permissions, permission)
 }
@@ -0,0 +1,68 @@
+package key
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"strings"
+
+	"github.com/geekswamp/zen/internal/errors"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Algorithm is a JWT signing algorithm name as used in the "alg" header.
+type Algorithm string
+
+const (
+	// RS256 is RSASSA-PKCS1-v1_5 using SHA-256.
+	RS256 Algorithm = "RS256"
+
+	// ES256 is ECDSA using P-256 and SHA-256.
+	ES256 Algorithm = "ES256"
+
+	// ES384 is ECDSA using P-384 and SHA-384.
+	ES384 Algorithm = "ES384"
+
+	// EdDSA is EdDSA using Ed25519.
+	EdDSA Algorithm = "EdDSA"
+)
+
+// ParseAlgorithm returns the Algorithm matching name, ignoring case.
+// An empty name defaults to RS256.
+func ParseAlgorithm(name string) (Algorithm, error) {
+	if name == "" {
+		return RS256, nil
+	}
+
+	for _, alg := range []Algorithm{RS256, ES256, ES384, EdDSA} {
+		if strings.EqualFold(name, string(alg)) {
+			return alg, nil
+		}
+	}
+
+	return "", errors.ErrUnsupportedAlgorithm
+}
+
+// SigningMethod returns the jwt signing method of the algorithm.
+func (a Algorithm) SigningMethod() jwt.SigningMethod {
+	return jwt.GetSigningMethod(string(a))
+}
+
+// curve returns the elliptic curve required by an ECDSA algorithm.
+func (a Algorithm) curve() elliptic.Curve {
+	switch a {
+	case ES256:
+		return elliptic.P256()
+	case ES384:
+		return elliptic.P384()
+	default:
+		return nil
+	}
+}
+
+func (a Algorithm) checkCurve(key *ecdsa.PublicKey) error {
+	if key.Curve != a.curve() {
+		return errors.ErrKeyAlgorithmMismatch
+	}
+
+	return nil
+}
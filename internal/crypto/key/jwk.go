@@ -0,0 +1,128 @@
+package key
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+
+	"github.com/geekswamp/zen/internal/errors"
+)
+
+// JWK is the public part of a key in the JSON Web Key format (RFC 7517).
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid,omitempty"`
+	Use       string `json:"use,omitempty"`
+	Algorithm string `json:"alg,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+}
+
+// JWKSet is a set of JSON Web Keys as served from a JWKS endpoint.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewJWKSet returns the public keys of every trusted key in the ring.
+func NewJWKSet(ring KeyRing) (JWKSet, error) {
+	set := JWKSet{Keys: []JWK{}}
+
+	for _, k := range ring.Keys() {
+		jwk, err := NewJWK(k)
+		if err != nil {
+			return JWKSet{}, err
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set, nil
+}
+
+// NewJWK returns the public key of the provider as a signature JWK.
+func NewJWK(provider KeyProvider) (JWK, error) {
+	jwk, err := publicJWK(provider.GetPublicKey())
+	if err != nil {
+		return JWK{}, err
+	}
+
+	jwk.KeyID = provider.GetKeyID()
+	jwk.Use = "sig"
+	jwk.Algorithm = provider.GetSigningMethod().Alg()
+
+	return jwk, nil
+}
+
+// Thumbprint computes the RFC 7638 SHA-256 thumbprint of the provider's public key.
+func Thumbprint(provider KeyProvider) (string, error) {
+	jwk, err := publicJWK(provider.GetPublicKey())
+	if err != nil {
+		return "", err
+	}
+
+	// The thumbprint only covers the required members, serialized with sorted keys.
+	members := map[string]string{"kty": jwk.KeyType}
+	switch jwk.KeyType {
+	case "RSA":
+		members["n"], members["e"] = jwk.N, jwk.E
+	case "EC":
+		members["crv"], members["x"], members["y"] = jwk.Curve, jwk.X, jwk.Y
+	case "OKP":
+		members["crv"], members["x"] = jwk.Curve, jwk.X
+	}
+
+	b, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(b)
+
+	return encodeSegment(sum[:]), nil
+}
+
+func publicJWK(publicKey crypto.PublicKey) (JWK, error) {
+	switch pub := publicKey.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			KeyType: "RSA",
+			N:       encodeSegment(pub.N.Bytes()),
+			E:       encodeSegment(big.NewInt(int64(pub.E)).Bytes()),
+		}, nil
+	case *ecdsa.PublicKey:
+		ecdhKey, err := pub.ECDH()
+		if err != nil {
+			return JWK{}, err
+		}
+
+		// Uncompressed point encoding: 0x04 || X || Y, both coordinates padded to the curve size.
+		point := ecdhKey.Bytes()[1:]
+		size := len(point) / 2
+
+		return JWK{
+			KeyType: "EC",
+			Curve:   pub.Curve.Params().Name,
+			X:       encodeSegment(point[:size]),
+			Y:       encodeSegment(point[size:]),
+		}, nil
+	case ed25519.PublicKey:
+		return JWK{
+			KeyType: "OKP",
+			Curve:   "Ed25519",
+			X:       encodeSegment(pub),
+		}, nil
+	default:
+		return JWK{}, errors.ErrUnsupportedAlgorithm
+	}
+}
+
+func encodeSegment(src []byte) string {
+	return base64.RawURLEncoding.EncodeToString(src)
+}
@@ -1,66 +1,178 @@
 package key
 
 import (
-	"crypto/rsa"
+	"crypto"
 	"os"
+	"time"
 
 	"github.com/geekswamp/zen/configs"
+	"github.com/geekswamp/zen/internal/errors"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-type RSAKeyPair struct {
-	privateKey *rsa.PrivateKey
-	publicKey  *rsa.PublicKey
+type KeyPair struct {
+	keyID      string
+	privateKey crypto.PrivateKey
+	publicKey  crypto.PublicKey
+	algorithm  Algorithm
 }
 
-func New() (RSAKeyProvider, error) {
+// NewKeyPair creates a KeyProvider from parsed keys. The private key may be nil for
+// keys that are only used for verification. An empty kid defaults to the RFC 7638
+// thumbprint of the public key.
+func NewKeyPair(kid string, alg Algorithm, privateKey crypto.PrivateKey, publicKey crypto.PublicKey) (KeyProvider, error) {
+	k := &KeyPair{keyID: kid, privateKey: privateKey, publicKey: publicKey, algorithm: alg}
+
+	if k.keyID == "" {
+		thumbprint, err := Thumbprint(k)
+		if err != nil {
+			return nil, err
+		}
+		k.keyID = thumbprint
+	}
+
+	return k, nil
+}
+
+// New loads the key ring from the config. The key pair in jwt.priv-key-path and jwt.pub-key-path,
+// parsed according to jwt.algorithm, is the active signing key. The public keys listed in
+// jwt.retired-keys are still accepted for verification until their configured time.
+func New() (KeyRing, error) {
 	cfg := configs.Get()
 
-	privateKey, err := loadPrivateKey(cfg)
+	alg, err := ParseAlgorithm(cfg.JWT.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, err := loadPrivateKey(cfg.JWT.PrivKeyPath, alg)
 	if err != nil {
 		return nil, err
 	}
 
-	publicKey, err := loadPublicKey(cfg)
+	publicKey, err := loadPublicKey(cfg.JWT.PubKeyPath, alg)
 	if err != nil {
 		return nil, err
 	}
 
-	return &RSAKeyPair{
-		privateKey: privateKey,
-		publicKey:  publicKey,
-	}, nil
+	active, err := NewKeyPair(cfg.JWT.KeyID, alg, privateKey, publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	retired := make([]RetiredKey, 0, len(cfg.JWT.RetiredKeys))
+	for _, r := range cfg.JWT.RetiredKeys {
+		retiredAlg, err := ParseAlgorithm(r.Algorithm)
+		if err != nil {
+			return nil, err
+		}
+
+		until, err := time.Parse(time.RFC3339, r.Until)
+		if err != nil {
+			return nil, errors.ErrInvalidRetiredKey
+		}
+
+		retiredPublicKey, err := loadPublicKey(r.PubKeyPath, retiredAlg)
+		if err != nil {
+			return nil, err
+		}
+
+		provider, err := NewKeyPair(r.KeyID, retiredAlg, nil, retiredPublicKey)
+		if err != nil {
+			return nil, err
+		}
+
+		retired = append(retired, RetiredKey{Provider: provider, Until: until})
+	}
+
+	return NewRing(active, retired...), nil
+}
+
+func (k *KeyPair) GetKeyID() string {
+	return k.keyID
+}
+
+func (k *KeyPair) GetPrivateKey() crypto.PrivateKey {
+	return k.privateKey
 }
 
-func (r *RSAKeyPair) GetPrivateKey() *rsa.PrivateKey {
-	return r.privateKey
+func (k *KeyPair) GetPublicKey() crypto.PublicKey {
+	return k.publicKey
 }
 
-func (r *RSAKeyPair) GetPublicKey() *rsa.PublicKey {
-	return r.publicKey
+func (k *KeyPair) GetSigningMethod() jwt.SigningMethod {
+	return k.algorithm.SigningMethod()
 }
 
-func loadPrivateKey(config configs.Config) (*rsa.PrivateKey, error) {
-	keyData, err := os.ReadFile(config.JWT.PrivKeyPath)
+func loadPrivateKey(path string, alg Algorithm) (crypto.PrivateKey, error) {
+	keyData, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
 
-	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(keyData)
+	return ParsePrivateKey(keyData, alg)
+}
+
+func loadPublicKey(path string, alg Algorithm) (crypto.PublicKey, error) {
+	keyData, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
 
-	return privateKey, nil
+	return ParsePublicKey(keyData, alg)
 }
 
-func loadPublicKey(config configs.Config) (*rsa.PublicKey, error) {
-	keyData, err := os.ReadFile(config.JWT.PubKeyPath)
+// ParsePrivateKey parses a PEM encoded private key of the given algorithm.
+func ParsePrivateKey(keyData []byte, alg Algorithm) (crypto.PrivateKey, error) {
+	var (
+		privateKey crypto.PrivateKey
+		err        error
+	)
+
+	switch alg {
+	case RS256:
+		privateKey, err = jwt.ParseRSAPrivateKeyFromPEM(keyData)
+	case ES256, ES384:
+		ecKey, ecErr := jwt.ParseECPrivateKeyFromPEM(keyData)
+		if ecErr != nil {
+			return nil, ecErr
+		}
+		privateKey, err = ecKey, alg.checkCurve(&ecKey.PublicKey)
+	case EdDSA:
+		privateKey, err = jwt.ParseEdPrivateKeyFromPEM(keyData)
+	default:
+		return nil, errors.ErrUnsupportedAlgorithm
+	}
+
 	if err != nil {
 		return nil, err
 	}
 
-	publicKey, err := jwt.ParseRSAPublicKeyFromPEM(keyData)
+	return privateKey, nil
+}
+
+// ParsePublicKey parses a PEM encoded public key of the given algorithm.
+func ParsePublicKey(keyData []byte, alg Algorithm) (crypto.PublicKey, error) {
+	var (
+		publicKey crypto.PublicKey
+		err       error
+	)
+
+	switch alg {
+	case RS256:
+		publicKey, err = jwt.ParseRSAPublicKeyFromPEM(keyData)
+	case ES256, ES384:
+		ecKey, ecErr := jwt.ParseECPublicKeyFromPEM(keyData)
+		if ecErr != nil {
+			return nil, ecErr
+		}
+		publicKey, err = ecKey, alg.checkCurve(ecKey)
+	case EdDSA:
+		publicKey, err = jwt.ParseEdPublicKeyFromPEM(keyData)
+	default:
+		return nil, errors.ErrUnsupportedAlgorithm
+	}
+
 	if err != nil {
 		return nil, err
 	}
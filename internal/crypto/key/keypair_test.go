@@ -1,30 +1,50 @@
 package key_test
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"testing"
+	"time"
 
 	"github.com/geekswamp/zen/internal/crypto/key"
+	"github.com/geekswamp/zen/internal/errors"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
-type MockRSAKeyProvider struct {
+type MockKeyProvider struct {
 	mock.Mock
 }
 
-func (m *MockRSAKeyProvider) GetPrivateKey() *rsa.PrivateKey {
+func (m *MockKeyProvider) GetKeyID() string {
 	args := m.Called()
-	return args.Get(0).(*rsa.PrivateKey)
+	return args.String(0)
 }
 
-func (m *MockRSAKeyProvider) GetPublicKey() *rsa.PublicKey {
+func (m *MockKeyProvider) GetPrivateKey() crypto.PrivateKey {
 	args := m.Called()
-	return args.Get(0).(*rsa.PublicKey)
+	return args.Get(0).(crypto.PrivateKey)
+}
+
+func (m *MockKeyProvider) GetPublicKey() crypto.PublicKey {
+	args := m.Called()
+	return args.Get(0).(crypto.PublicKey)
+}
+
+func (m *MockKeyProvider) GetSigningMethod() jwt.SigningMethod {
+	args := m.Called()
+	return args.Get(0).(jwt.SigningMethod)
 }
 
 func TestGetPrivateKey(t *testing.T) {
-	mockProvider := new(MockRSAKeyProvider)
+	mockProvider := new(MockKeyProvider)
 	mockKey := &rsa.PrivateKey{}
 
 	mockProvider.On("GetPrivateKey").Return(mockKey)
@@ -35,7 +55,7 @@ func TestGetPrivateKey(t *testing.T) {
 }
 
 func TestGetPublicKey(t *testing.T) {
-	mockProvider := new(MockRSAKeyProvider)
+	mockProvider := new(MockKeyProvider)
 	mockKey := &rsa.PublicKey{}
 
 	mockProvider.On("GetPublicKey").Return(mockKey)
@@ -45,7 +65,143 @@ func TestGetPublicKey(t *testing.T) {
 	mockProvider.AssertCalled(t, "GetPublicKey")
 }
 
-func BenchmarkNewRSAKeyProvider(b *testing.B) {
+func TestParseAlgorithm(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   string
+		want    key.Algorithm
+		wantErr error
+	}{
+		{name: "Empty Defaults To RS256", input: "", want: key.RS256},
+		{name: "Exact Name", input: "ES384", want: key.ES384},
+		{name: "Case Insensitive", input: "eddsa", want: key.EdDSA},
+		{name: "Unsupported", input: "HS256", wantErr: errors.ErrUnsupportedAlgorithm},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			alg, err := key.ParseAlgorithm(tc.input)
+
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, alg)
+		})
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		alg        key.Algorithm
+		privateKey crypto.Signer
+		wantErr    bool
+	}{
+		{name: "RS256", alg: key.RS256, privateKey: rsaKey},
+		{name: "ES256", alg: key.ES256, privateKey: p256Key},
+		{name: "ES384", alg: key.ES384, privateKey: p384Key},
+		{name: "EdDSA", alg: key.EdDSA, privateKey: edKey},
+		{name: "Curve Mismatch", alg: key.ES256, privateKey: p384Key, wantErr: true},
+		{name: "Type Mismatch", alg: key.EdDSA, privateKey: rsaKey, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			privDER, err := x509.MarshalPKCS8PrivateKey(tc.privateKey)
+			require.NoError(t, err)
+
+			pubDER, err := x509.MarshalPKIXPublicKey(tc.privateKey.Public())
+			require.NoError(t, err)
+
+			privateKey, privErr := key.ParsePrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER}), tc.alg)
+			publicKey, pubErr := key.ParsePublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}), tc.alg)
+
+			if tc.wantErr {
+				require.Error(t, privErr)
+				require.Error(t, pubErr)
+				return
+			}
+
+			require.NoError(t, privErr)
+			require.NoError(t, pubErr)
+			require.NotNil(t, privateKey)
+			require.NotNil(t, publicKey)
+		})
+	}
+}
+
+func TestJWKSet(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	edPublicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	active, err := key.NewKeyPair("", key.RS256, rsaKey, &rsaKey.PublicKey)
+	require.NoError(t, err)
+
+	ecRetired, err := key.NewKeyPair("ec", key.ES384, nil, &p384Key.PublicKey)
+	require.NoError(t, err)
+
+	edRetired, err := key.NewKeyPair("ed", key.EdDSA, nil, edPublicKey)
+	require.NoError(t, err)
+
+	expired, err := key.NewKeyPair("expired", key.EdDSA, nil, edPublicKey)
+	require.NoError(t, err)
+
+	ring := key.NewRing(active,
+		key.RetiredKey{Provider: ecRetired, Until: time.Now().Add(time.Hour)},
+		key.RetiredKey{Provider: edRetired, Until: time.Now().Add(time.Hour)},
+		key.RetiredKey{Provider: expired, Until: time.Now().Add(-time.Hour)},
+	)
+
+	set, err := key.NewJWKSet(ring)
+	require.NoError(t, err)
+	require.Len(t, set.Keys, 3)
+
+	thumbprint, err := key.Thumbprint(active)
+	require.NoError(t, err)
+
+	rsaJWK := set.Keys[0]
+	require.Equal(t, thumbprint, rsaJWK.KeyID)
+	require.Equal(t, "RSA", rsaJWK.KeyType)
+	require.Equal(t, "RS256", rsaJWK.Algorithm)
+	require.Equal(t, "AQAB", rsaJWK.E)
+
+	ecJWK := set.Keys[1]
+	require.Equal(t, "ec", ecJWK.KeyID)
+	require.Equal(t, "P-384", ecJWK.Curve)
+	require.Len(t, ecJWK.X, 64)
+	require.Len(t, ecJWK.Y, 64)
+
+	edJWK := set.Keys[2]
+	require.Equal(t, "OKP", edJWK.KeyType)
+	require.Equal(t, "Ed25519", edJWK.Curve)
+	require.Equal(t, "EdDSA", edJWK.Algorithm)
+
+	_, err = ring.Lookup("expired")
+	require.ErrorIs(t, err, errors.ErrUnknownKeyID)
+}
+
+func BenchmarkNewKeyProvider(b *testing.B) {
 	for b.Loop() {
 		_, _ = key.New()
 	}
@@ -1,8 +1,27 @@
 package key
 
-import "crypto/rsa"
+import (
+	"crypto"
 
-type RSAKeyProvider interface {
-	GetPrivateKey() *rsa.PrivateKey
-	GetPublicKey() *rsa.PublicKey
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// KeyProvider provides a key pair identified by its kid and the matching signing method used for JWTs.
+type KeyProvider interface {
+	GetKeyID() string
+	GetPrivateKey() crypto.PrivateKey
+	GetPublicKey() crypto.PublicKey
+	GetSigningMethod() jwt.SigningMethod
+}
+
+// KeyRing holds the key used to sign new tokens and every key still trusted to verify them.
+type KeyRing interface {
+	// Active returns the key used to sign new tokens.
+	Active() KeyProvider
+
+	// Lookup returns the trusted key with the given kid.
+	Lookup(kid string) (KeyProvider, error)
+
+	// Keys returns every trusted key, starting with the active one.
+	Keys() []KeyProvider
 }
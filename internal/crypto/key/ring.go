@@ -0,0 +1,51 @@
+package key
+
+import (
+	"time"
+
+	"github.com/geekswamp/zen/internal/errors"
+)
+
+// RetiredKey is a key that no longer signs tokens but is accepted for verification until Until,
+// so that tokens issued before a rotation stay valid until they expire.
+type RetiredKey struct {
+	Provider KeyProvider
+	Until    time.Time
+}
+
+type Ring struct {
+	active  KeyProvider
+	retired []RetiredKey
+}
+
+// NewRing creates a KeyRing signing with active and also trusting the given retired keys.
+func NewRing(active KeyProvider, retired ...RetiredKey) KeyRing {
+	return &Ring{active: active, retired: retired}
+}
+
+func (r *Ring) Active() KeyProvider {
+	return r.active
+}
+
+func (r *Ring) Lookup(kid string) (KeyProvider, error) {
+	for _, k := range r.Keys() {
+		if k.GetKeyID() == kid {
+			return k, nil
+		}
+	}
+
+	return nil, errors.ErrUnknownKeyID
+}
+
+func (r *Ring) Keys() []KeyProvider {
+	now := time.Now()
+	keys := []KeyProvider{r.active}
+
+	for _, k := range r.retired {
+		if now.Before(k.Until) {
+			keys = append(keys, k.Provider)
+		}
+	}
+
+	return keys
+}
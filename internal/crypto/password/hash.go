@@ -4,6 +4,7 @@ import (
 	"crypto/subtle"
 	"encoding/base64"
 	"fmt"
+	"strings"
 
 	"github.com/geekswamp/zen/configs"
 	"github.com/geekswamp/zen/internal/crypto/rand"
@@ -20,6 +21,12 @@ type Raw struct {
 	Hash   []byte
 }
 
+// Result holds the outcome of verifying a password against an encoded hash.
+type Result struct {
+	Valid       bool
+	NeedsRehash bool
+}
+
 type Config struct {
 	pepper      string
 	memory      uint32
@@ -99,6 +106,86 @@ func (r *Raw) Verify(text []byte, hash string) (bool, error) {
 	return subtle.ConstantTimeCompare(r.Hash, raw.Hash) == 1, nil
 }
 
+// Decode parses an encoded hash in the PHC string format produced by Generate.
+// The returned Raw carries the argon2id parameters embedded in the string.
+// The pepper is not part of the encoding, so it is left empty.
+func Decode(encoded string) (*Raw, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != "argon2id" {
+		return nil, errors.ErrInvalidHashFormat
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return nil, errors.ErrInvalidHashFormat
+	}
+
+	if version != argon2.Version {
+		return nil, errors.ErrIncompatibleArgon2Version
+	}
+
+	var config Config
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &config.memory, &config.iterations, &config.parallelism); err != nil {
+		return nil, errors.ErrInvalidHashFormat
+	}
+
+	if config.iterations == 0 || config.parallelism == 0 {
+		return nil, errors.ErrInvalidHashFormat
+	}
+
+	salt, err := decodeString(parts[4])
+	if err != nil || len(salt) == 0 {
+		return nil, errors.ErrFailedToDecodeHash
+	}
+
+	hash, err := decodeString(parts[5])
+	if err != nil || len(hash) == 0 {
+		return nil, errors.ErrFailedToDecodeHash
+	}
+
+	config.saltLength = uint32(len(salt))
+	config.keyLength = uint32(len(hash))
+
+	return &Raw{Config: config, Salt: salt, Hash: hash}, nil
+}
+
+// Verify checks text against an encoded hash using the pepper from the application config.
+func Verify(text []byte, encoded string) (*Result, error) {
+	pc := NewFromConfig(configs.Get())
+	return pc.Verify(text, encoded)
+}
+
+// Verify checks text against an encoded hash. The hash is recomputed with the parameters
+// embedded in the encoded string and the pepper of this config. When the text matches,
+// NeedsRehash reports whether the stored parameters are weaker than this config.
+func (a *Config) Verify(text []byte, encoded string) (*Result, error) {
+	raw, err := Decode(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	raw.Config.pepper = a.pepper
+
+	valid, err := raw.Verify(text, encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{Valid: valid, NeedsRehash: valid && raw.Config.isWeakerThan(*a)}, nil
+}
+
+func (a Config) isWeakerThan(target Config) bool {
+	return a.memory < target.memory ||
+		a.iterations < target.iterations ||
+		a.parallelism < target.parallelism ||
+		a.saltLength < target.saltLength ||
+		a.keyLength < target.keyLength
+}
+
 func encodeToString(src []byte) string {
 	return base64.RawStdEncoding.EncodeToString(src)
 }
+
+func decodeString(src string) ([]byte, error) {
+	return base64.RawStdEncoding.DecodeString(src)
+}
@@ -0,0 +1,128 @@
+package password_test
+
+import (
+	"testing"
+
+	"github.com/geekswamp/zen/internal/crypto/password"
+	"github.com/geekswamp/zen/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	testCases := []struct {
+		name    string
+		encoded string
+		wantErr error
+	}{
+		{
+			name:    "Valid Hash",
+			encoded: "$argon2id$v=19$m=12288,t=3,p=1$CiI6u9dtw8jTTkjFCfqxVD3JX6n4kNoF+sWUMwp9z28$qc2gq3BDYWFQb4v24cjnJDNgQuI9eUrOUxjvL9wUezw",
+			wantErr: nil,
+		},
+		{
+			name:    "Wrong Algorithm",
+			encoded: "$argon2i$v=19$m=12288,t=3,p=1$c2FsdA$aGFzaA",
+			wantErr: errors.ErrInvalidHashFormat,
+		},
+		{
+			name:    "Missing Parts",
+			encoded: "$argon2id$v=19$m=12288,t=3,p=1$c2FsdA",
+			wantErr: errors.ErrInvalidHashFormat,
+		},
+		{
+			name:    "Incompatible Version",
+			encoded: "$argon2id$v=16$m=12288,t=3,p=1$c2FsdA$aGFzaA",
+			wantErr: errors.ErrIncompatibleArgon2Version,
+		},
+		{
+			name:    "Malformed Parameters",
+			encoded: "$argon2id$v=19$m=abc,t=3,p=1$c2FsdA$aGFzaA",
+			wantErr: errors.ErrInvalidHashFormat,
+		},
+		{
+			name:    "Invalid Base64 Salt",
+			encoded: "$argon2id$v=19$m=12288,t=3,p=1$!!!$aGFzaA",
+			wantErr: errors.ErrFailedToDecodeHash,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			raw, err := password.Decode(tc.encoded)
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.Nil(t, raw)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, raw.Salt, 32)
+			assert.Len(t, raw.Hash, 32)
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	weak := password.New("pepper", 8192, 1, 16, 32, 1)
+	current := password.New("pepper", 12288, 3, 32, 32, 1)
+
+	weakHash, err := weak.Generate([]byte("secret-password"))
+	require.NoError(t, err)
+
+	currentHash, err := current.Generate([]byte("secret-password"))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name            string
+		config          password.Config
+		text            string
+		encoded         string
+		wantValid       bool
+		wantNeedsRehash bool
+	}{
+		{
+			name:            "Current Parameters",
+			config:          current,
+			text:            "secret-password",
+			encoded:         currentHash,
+			wantValid:       true,
+			wantNeedsRehash: false,
+		},
+		{
+			name:            "Weaker Stored Parameters",
+			config:          current,
+			text:            "secret-password",
+			encoded:         weakHash,
+			wantValid:       true,
+			wantNeedsRehash: true,
+		},
+		{
+			name:            "Wrong Password",
+			config:          current,
+			text:            "wrong-password",
+			encoded:         weakHash,
+			wantValid:       false,
+			wantNeedsRehash: false,
+		},
+		{
+			name:            "Wrong Pepper",
+			config:          password.New("other", 12288, 3, 32, 32, 1),
+			text:            "secret-password",
+			encoded:         currentHash,
+			wantValid:       false,
+			wantNeedsRehash: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := tc.config.Verify([]byte(tc.text), tc.encoded)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantValid, result.Valid)
+			assert.Equal(t, tc.wantNeedsRehash, result.NeedsRehash)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package token
+
+import "github.com/golang-jwt/jwt/v5"
+
+// Claims is the set of claims carried by the tokens of this package. It embeds the
+// registered claims and adds application specific ones. Claims without a dedicated
+// field can be stored in Extra.
+type Claims struct {
+	jwt.RegisteredClaims
+	SessionID   string         `json:"sid,omitempty"`
+	Roles       []string       `json:"roles,omitempty"`
+	Permissions []string       `json:"perms,omitempty"`
+	Extra       map[string]any `json:"ext,omitempty"`
+}
+
+// ClaimsOption sets optional claims on a token created with New.
+type ClaimsOption func(c *Claims)
+
+// WithSessionID sets the session the token belongs to.
+func WithSessionID(id string) ClaimsOption {
+	return func(c *Claims) { c.SessionID = id }
+}
+
+// WithRoles sets the roles of the subject and the permissions they grant.
+func WithRoles(roles, permissions []string) ClaimsOption {
+	return func(c *Claims) {
+		c.Roles = roles
+		c.Permissions = permissions
+	}
+}
+
+// WithExtra adds a custom claim under the given key.
+func WithExtra(key string, value any) ClaimsOption {
+	return func(c *Claims) {
+		if c.Extra == nil {
+			c.Extra = map[string]any{}
+		}
+		c.Extra[key] = value
+	}
+}
@@ -1,24 +1,28 @@
 package token
 
 import (
+	"errors"
+	"slices"
 	"time"
 
 	"github.com/geekswamp/zen/internal/crypto/key"
-	"github.com/geekswamp/zen/internal/errors"
+	errs "github.com/geekswamp/zen/internal/errors"
 	"github.com/geekswamp/zen/internal/logger"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
 
+const _KeyIDHeader = "kid"
+
 type JWTHash struct {
-	jwt.RegisteredClaims
-	key.RSAKeyProvider
+	Claims
+	keys key.KeyRing
 }
 
-func New(iss, sub string, aud jwt.ClaimStrings, exp time.Duration, provider key.RSAKeyProvider) JWTProvider {
+func New(iss, sub string, aud jwt.ClaimStrings, exp time.Duration, keys key.KeyRing, opts ...ClaimsOption) JWTProvider {
 	now := time.Now()
 
-	return &JWTHash{
+	claims := Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
 			ID:        uuid.NewString(),
 			Issuer:    iss,
@@ -28,56 +32,105 @@ func New(iss, sub string, aud jwt.ClaimStrings, exp time.Duration, provider key.
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
 		},
-		RSAKeyProvider: provider,
+	}
+
+	for _, opt := range opts {
+		opt(&claims)
+	}
+
+	return &JWTHash{
+		Claims: claims,
+		keys:   keys,
+	}
+}
+
+// NewVerifier creates a JWTProvider that is only used to verify tokens signed by keys of the given ring.
+// Verified tokens must be issued by iss and contain at least one of aud in their audience.
+func NewVerifier(iss string, aud jwt.ClaimStrings, keys key.KeyRing) JWTProvider {
+	return &JWTHash{
+		Claims: Claims{RegisteredClaims: jwt.RegisteredClaims{Issuer: iss, Audience: aud}},
+		keys:   keys,
 	}
 }
 
+// Generate signs the claims with the active key of the ring and writes its kid into the token header.
 func (j *JWTHash) Generate() (hash string, err error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, &j.RegisteredClaims)
+	active := j.keys.Active()
+	token := jwt.NewWithClaims(active.GetSigningMethod(), &j.Claims)
+	token.Header[_KeyIDHeader] = active.GetKeyID()
 
-	hash, err = token.SignedString(j.GetPrivateKey())
+	hash, err = token.SignedString(active.GetPrivateKey())
 	if err != nil {
-		log.Error(errors.ErrFailedToSignToken.Error(), logger.ErrDetails(err))
-		return "", errors.ErrFailedToSignToken
+		log.Error(errs.ErrFailedToSignToken.Error(), logger.ErrDetails(err))
+		return "", errs.ErrFailedToSignToken
 	}
 
 	return hash, nil
 }
 
-func (j *JWTHash) Verify(tokenStr string) (claims *jwt.RegisteredClaims, err error) {
-	pubKey := j.GetPublicKey()
-	if pubKey == nil {
-		log.Error(errors.ErrNilPubKey.Error())
-		return nil, errors.ErrInvalidToken
+// Verify parses and validates the token. The verification key is selected by the kid header,
+// tokens without one are checked against the active key. Besides the signature and time based
+// claims, the issuer and audience are checked against the ones of this provider.
+func (j *JWTHash) Verify(tokenStr string) (claims *Claims, err error) {
+	opts := []jwt.ParserOption{jwt.WithExpirationRequired()}
+	if j.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(j.Issuer))
 	}
 
-	token, err := jwt.ParseWithClaims(tokenStr, &jwt.RegisteredClaims{}, func(t *jwt.Token) (any, error) {
-		if _, ok := t.Method.(*jwt.SigningMethodRSA); !ok {
-			log.Error(errors.ErrFailedToSignToken.Error(), logger.ErrDetails(err))
-			return nil, errors.ErrFailedToSignToken
+	token, err := jwt.ParseWithClaims(tokenStr, &Claims{}, func(t *jwt.Token) (any, error) {
+		provider, err := j.lookupKey(t)
+		if err != nil {
+			log.Error(err.Error())
+			return nil, err
+		}
+
+		if provider.GetPublicKey() == nil {
+			log.Error(errs.ErrNilPubKey.Error())
+			return nil, errs.ErrNilPubKey
+		}
+
+		if t.Method.Alg() != provider.GetSigningMethod().Alg() {
+			log.Error(errs.ErrFailedToSignToken.Error())
+			return nil, errs.ErrFailedToSignToken
 		}
-		return pubKey, nil
-	})
+		return provider.GetPublicKey(), nil
+	}, opts...)
 
 	if err != nil {
-		if err == jwt.ErrTokenExpired {
-			return nil, err
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, jwt.ErrTokenExpired
 		}
 
-		log.Error(errors.ErrFailedTokenParsing.Error(), logger.ErrDetails(err))
-		return nil, errors.ErrInvalidToken
+		log.Error(errs.ErrFailedTokenParsing.Error(), logger.ErrDetails(err))
+		return nil, errs.ErrInvalidToken
 	}
 
 	if !token.Valid {
-		log.Error(errors.ErrInvalidToken.Error())
-		return nil, errors.ErrInvalidToken
+		log.Error(errs.ErrInvalidToken.Error())
+		return nil, errs.ErrInvalidToken
 	}
 
-	claims, ok := token.Claims.(*jwt.RegisteredClaims)
+	claims, ok := token.Claims.(*Claims)
 	if !ok {
-		log.Error(errors.ErrInvalidToken.Error(), logger.ErrDetails(err))
-		return nil, errors.ErrInvalidToken
+		log.Error(errs.ErrInvalidToken.Error(), logger.ErrDetails(err))
+		return nil, errs.ErrInvalidToken
+	}
+
+	if len(j.Audience) > 0 && !slices.ContainsFunc(claims.Audience, func(aud string) bool {
+		return slices.Contains(j.Audience, aud)
+	}) {
+		log.Error(errs.ErrInvalidToken.Error(), logger.ErrDetails(jwt.ErrTokenInvalidAudience))
+		return nil, errs.ErrInvalidToken
 	}
 
 	return claims, nil
 }
+
+func (j *JWTHash) lookupKey(t *jwt.Token) (key.KeyProvider, error) {
+	kid, ok := t.Header[_KeyIDHeader].(string)
+	if !ok {
+		return j.keys.Active(), nil
+	}
+
+	return j.keys.Lookup(kid)
+}
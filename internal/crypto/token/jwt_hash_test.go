@@ -1,55 +1,89 @@
 package token_test
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"errors"
 	"testing"
 	"time"
 
+	"github.com/geekswamp/zen/internal/crypto/key"
+	"github.com/geekswamp/zen/internal/crypto/token"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
-type MockRSAKeyProvider struct {
+type MockKeyProvider struct {
 	mock.Mock
-	privateKey *rsa.PrivateKey
-	publicKey  *rsa.PublicKey
+	keyID      string
+	privateKey crypto.PrivateKey
+	publicKey  crypto.PublicKey
+	method     jwt.SigningMethod
 }
 
 type MockJWTProvider struct {
 	mock.Mock
 }
 
-func (m *MockRSAKeyProvider) GetPrivateKey() *rsa.PrivateKey {
+func (m *MockKeyProvider) GetKeyID() string {
+	return m.keyID
+}
+
+func (m *MockKeyProvider) GetPrivateKey() crypto.PrivateKey {
 	return m.privateKey
 }
 
-func (m *MockRSAKeyProvider) GetPublicKey() *rsa.PublicKey {
+func (m *MockKeyProvider) GetPublicKey() crypto.PublicKey {
 	return m.publicKey
 }
 
+func (m *MockKeyProvider) GetSigningMethod() jwt.SigningMethod {
+	return m.method
+}
+
 func (m *MockJWTProvider) Generate() (hash string, err error) {
 	args := m.Called()
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockJWTProvider) Verify(tokenStr string) (claims *jwt.RegisteredClaims, err error) {
+func (m *MockJWTProvider) Verify(tokenStr string) (claims *token.Claims, err error) {
 	args := m.Called(tokenStr)
-	if claims, ok := args.Get(0).(*jwt.RegisteredClaims); ok {
+	if claims, ok := args.Get(0).(*token.Claims); ok {
 		return claims, args.Error(1)
 	}
 	return nil, args.Error(1)
 }
 
-func generateTestKeys() (*rsa.PrivateKey, *rsa.PublicKey, error) {
-	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
-	if err != nil {
-		return nil, nil, err
+func generateTestKeys(method jwt.SigningMethod) (*MockKeyProvider, error) {
+	switch method {
+	case jwt.SigningMethodES256, jwt.SigningMethodES384:
+		curve := elliptic.P256()
+		if method == jwt.SigningMethodES384 {
+			curve = elliptic.P384()
+		}
+		privateKey, err := ecdsa.GenerateKey(curve, rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		return &MockKeyProvider{keyID: method.Alg(), privateKey: privateKey, publicKey: &privateKey.PublicKey, method: method}, nil
+	case jwt.SigningMethodEdDSA:
+		publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		return &MockKeyProvider{keyID: method.Alg(), privateKey: privateKey, publicKey: publicKey, method: method}, nil
+	default:
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, err
+		}
+		return &MockKeyProvider{keyID: method.Alg(), privateKey: privateKey, publicKey: &privateKey.PublicKey, method: method}, nil
 	}
-	publicKey := &privateKey.PublicKey
-	return privateKey, publicKey, nil
 }
 
 func TestGenerateToken(t *testing.T) {
@@ -109,11 +143,14 @@ func TestVerify(t *testing.T) {
 	invalidToken := "invalid.jwt.token"
 	emptyToken := ""
 
-	expectedClaims := &jwt.RegisteredClaims{
-		Issuer:    "test_issuer",
-		Subject:   "test_subject",
-		Audience:  jwt.ClaimStrings{"test_audience"},
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	expectedClaims := &token.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "test_issuer",
+			Subject:   "test_subject",
+			Audience:  jwt.ClaimStrings{"test_audience"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		SessionID: "test_session",
 	}
 
 	mockJWT.On("Verify", validToken).Return(expectedClaims, nil).Once()
@@ -172,3 +209,150 @@ func TestVerify(t *testing.T) {
 
 	mockJWT.AssertExpectations(t)
 }
+
+func TestGenerateAndVerify(t *testing.T) {
+	provider, err := generateTestKeys(jwt.SigningMethodRS256)
+	assert.NoError(t, err)
+
+	ring := key.NewRing(provider)
+
+	tokenStr, err := token.New("test_issuer", "test_subject", jwt.ClaimStrings{"test_audience"}, time.Hour, ring,
+		token.WithSessionID("test_session"),
+		token.WithExtra("tenant", "test_tenant"),
+	).Generate()
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		verifier token.JWTProvider
+		wantErr  bool
+	}{
+		{
+			name:     "Matching Issuer and Audience",
+			verifier: token.NewVerifier("test_issuer", jwt.ClaimStrings{"other_audience", "test_audience"}, ring),
+			wantErr:  false,
+		},
+		{
+			name:     "Wrong Issuer",
+			verifier: token.NewVerifier("other_issuer", jwt.ClaimStrings{"test_audience"}, ring),
+			wantErr:  true,
+		},
+		{
+			name:     "Wrong Audience",
+			verifier: token.NewVerifier("test_issuer", jwt.ClaimStrings{"other_audience"}, ring),
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			claims, err := tc.verifier.Verify(tokenStr)
+
+			if tc.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, claims)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, "test_subject", claims.Subject)
+			assert.Equal(t, "test_session", claims.SessionID)
+			assert.Equal(t, "test_tenant", claims.Extra["tenant"])
+		})
+	}
+}
+
+func TestSigningAlgorithms(t *testing.T) {
+	methods := []jwt.SigningMethod{
+		jwt.SigningMethodRS256,
+		jwt.SigningMethodES256,
+		jwt.SigningMethodES384,
+		jwt.SigningMethodEdDSA,
+	}
+
+	for _, method := range methods {
+		t.Run(method.Alg(), func(t *testing.T) {
+			provider, err := generateTestKeys(method)
+			assert.NoError(t, err)
+
+			ring := key.NewRing(provider)
+
+			tokenStr, err := token.New("test_issuer", "test_subject", jwt.ClaimStrings{"test_audience"}, time.Hour, ring).Generate()
+			assert.NoError(t, err)
+
+			claims, err := token.NewVerifier("test_issuer", jwt.ClaimStrings{"test_audience"}, ring).Verify(tokenStr)
+			assert.NoError(t, err)
+			assert.Equal(t, "test_subject", claims.Subject)
+		})
+	}
+
+	rsaProvider, err := generateTestKeys(jwt.SigningMethodRS256)
+	assert.NoError(t, err)
+
+	edProvider, err := generateTestKeys(jwt.SigningMethodEdDSA)
+	assert.NoError(t, err)
+
+	// Same kid but a different algorithm must not be accepted.
+	edProvider.keyID = rsaProvider.keyID
+
+	tokenStr, err := token.New("test_issuer", "test_subject", nil, time.Hour, key.NewRing(rsaProvider)).Generate()
+	assert.NoError(t, err)
+
+	claims, err := token.NewVerifier("test_issuer", nil, key.NewRing(edProvider)).Verify(tokenStr)
+	assert.Error(t, err)
+	assert.Nil(t, claims)
+}
+
+func TestKeyRotation(t *testing.T) {
+	oldProvider, err := generateTestKeys(jwt.SigningMethodRS256)
+	assert.NoError(t, err)
+	oldProvider.keyID = "old"
+
+	newProvider, err := generateTestKeys(jwt.SigningMethodEdDSA)
+	assert.NoError(t, err)
+	newProvider.keyID = "new"
+
+	oldToken, err := token.New("test_issuer", "test_subject", nil, time.Hour, key.NewRing(oldProvider)).Generate()
+	assert.NoError(t, err)
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(oldToken, &token.Claims{})
+	assert.NoError(t, err)
+	assert.Equal(t, "old", parsed.Header["kid"])
+
+	testCases := []struct {
+		name    string
+		ring    key.KeyRing
+		wantErr bool
+	}{
+		{
+			name:    "Retired Key Still Trusted",
+			ring:    key.NewRing(newProvider, key.RetiredKey{Provider: oldProvider, Until: time.Now().Add(time.Hour)}),
+			wantErr: false,
+		},
+		{
+			name:    "Retired Key Past Until",
+			ring:    key.NewRing(newProvider, key.RetiredKey{Provider: oldProvider, Until: time.Now().Add(-time.Hour)}),
+			wantErr: true,
+		},
+		{
+			name:    "Unknown Key",
+			ring:    key.NewRing(newProvider),
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			claims, err := token.NewVerifier("test_issuer", nil, tc.ring).Verify(oldToken)
+
+			if tc.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, claims)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, "test_subject", claims.Subject)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package token
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+
+	"github.com/geekswamp/zen/internal/crypto/rand"
+)
+
+const _OpaqueTokenLength uint32 = 32
+
+// NewOpaque generates a random URL-safe token together with its SHA-256 digest.
+// Only the digest should be persisted, the plain token is handed to the client.
+func NewOpaque() (plain string, hash string, err error) {
+	b, err := rand.GenerateRandomBytes(_OpaqueTokenLength)
+	if err != nil {
+		return "", "", err
+	}
+
+	plain = base64.RawURLEncoding.EncodeToString(b)
+
+	return plain, HashOpaque(plain), nil
+}
+
+// HashOpaque returns the hex encoded SHA-256 digest of an opaque token.
+func HashOpaque(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
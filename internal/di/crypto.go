@@ -0,0 +1,28 @@
+package di
+
+import (
+	"github.com/geekswamp/zen/configs"
+	"github.com/geekswamp/zen/internal/crypto/key"
+	"github.com/geekswamp/zen/internal/crypto/token"
+	"github.com/google/wire"
+)
+
+var KeySet = wire.NewSet(InitKeyRing)
+
+var TokenSet = wire.NewSet(
+	KeySet,
+	InitVerifier,
+)
+
+func InitKeyRing() key.KeyRing {
+	ring, err := key.New()
+	if err != nil {
+		panic("failed to load JWT keys " + err.Error())
+	}
+	return ring
+}
+
+func InitVerifier(ring key.KeyRing) token.JWTProvider {
+	cfg := configs.Get()
+	return token.NewVerifier(cfg.JWT.Issuer, cfg.JWT.Audience, ring)
+}
@@ -1,12 +1,36 @@
 package di
 
 import (
+	"github.com/geekswamp/zen/configs"
+	"github.com/geekswamp/zen/internal/handler/v1/audit"
+	"github.com/geekswamp/zen/internal/handler/v1/auth"
 	"github.com/geekswamp/zen/internal/handler/v1/user"
+	"github.com/geekswamp/zen/internal/handler/wellknown"
 	"github.com/geekswamp/zen/internal/http"
 	"github.com/google/wire"
 )
 
 var UserHandlerSet = wire.NewSet(
 	http.New,
+	InitCursorCodec,
 	user.New,
 )
+
+func InitCursorCodec() http.CursorCodec {
+	return http.NewCursorCodec([]byte(configs.Get().Pagination.CursorSecret))
+}
+
+var AuthHandlerSet = wire.NewSet(
+	http.New,
+	auth.New,
+)
+
+var AuditHandlerSet = wire.NewSet(
+	http.New,
+	audit.New,
+)
+
+var WellKnownHandlerSet = wire.NewSet(
+	http.New,
+	wellknown.New,
+)
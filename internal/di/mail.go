@@ -0,0 +1,16 @@
+package di
+
+import (
+	"github.com/geekswamp/zen/internal/mail"
+	"github.com/google/wire"
+)
+
+var MailSet = wire.NewSet(InitMailer)
+
+func InitMailer() mail.Mailer {
+	mailer, err := mail.New()
+	if err != nil {
+		panic("failed to create mailer " + err.Error())
+	}
+	return mailer
+}
@@ -11,3 +11,13 @@ var UserRepositorySet = wire.NewSet(
 	base.NewRepo,
 	repository.NewUserRepo,
 )
+
+var RefreshTokenRepositorySet = wire.NewSet(repository.NewRefreshTokenRepo)
+
+var RoleRepositorySet = wire.NewSet(repository.NewRoleRepo)
+
+var VerificationTokenRepositorySet = wire.NewSet(repository.NewVerificationTokenRepo)
+
+var AuditRepositorySet = wire.NewSet(repository.NewAuditRepo)
+
+var TransactorSet = wire.NewSet(base.NewTransactor)
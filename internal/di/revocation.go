@@ -0,0 +1,27 @@
+package di
+
+import (
+	"sync"
+
+	"github.com/geekswamp/zen/configs"
+	"github.com/geekswamp/zen/internal/base"
+	"github.com/geekswamp/zen/internal/storage/revocation"
+	"github.com/google/wire"
+)
+
+var RevocationSet = wire.NewSet(NewRevocationStore)
+
+var (
+	revocationStore revocation.Store
+	revocationOnce  sync.Once
+)
+
+// NewRevocationStore returns the process-wide revocation store, so that revocations
+// made by one handler are seen at once by the auth middleware through the shared cache.
+func NewRevocationStore(repo base.Repository) revocation.Store {
+	revocationOnce.Do(func() {
+		revocationStore = revocation.NewCache(revocation.NewPostgres(repo), configs.Get().JWT.RevocationCacheTTL)
+	})
+
+	return revocationStore
+}
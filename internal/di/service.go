@@ -6,3 +6,10 @@ import (
 )
 
 var UserServiceSet = wire.NewSet(service.NewUserService)
+
+var AuditServiceSet = wire.NewSet(service.NewAuditService)
+
+var AuthServiceSet = wire.NewSet(
+	KeySet,
+	service.NewAuthService,
+)
@@ -4,7 +4,13 @@
 package di
 
 import (
+	"github.com/geekswamp/zen/internal/base"
+	"github.com/geekswamp/zen/internal/crypto/token"
+	"github.com/geekswamp/zen/internal/handler/v1/audit"
+	"github.com/geekswamp/zen/internal/handler/v1/auth"
 	"github.com/geekswamp/zen/internal/handler/v1/user"
+	"github.com/geekswamp/zen/internal/handler/wellknown"
+	"github.com/geekswamp/zen/internal/storage/revocation"
 	"github.com/google/wire"
 	"gorm.io/gorm"
 )
@@ -12,6 +18,13 @@ import (
 func InitUserHandler() user.UserHandler {
 	wire.Build(
 		UserRepositorySet,
+		TransactorSet,
+		RefreshTokenRepositorySet,
+		RoleRepositorySet,
+		VerificationTokenRepositorySet,
+		AuditRepositorySet,
+		RevocationSet,
+		MailSet,
 		UserServiceSet,
 		UserHandlerSet,
 	)
@@ -19,6 +32,56 @@ func InitUserHandler() user.UserHandler {
 	return user.UserHandler{}
 }
 
+func InitAuthHandler() auth.AuthHandler {
+	wire.Build(
+		UserRepositorySet,
+		TransactorSet,
+		RoleRepositorySet,
+		RefreshTokenRepositorySet,
+		VerificationTokenRepositorySet,
+		AuditRepositorySet,
+		RevocationSet,
+		MailSet,
+		AuthServiceSet,
+		AuthHandlerSet,
+	)
+
+	return auth.AuthHandler{}
+}
+
+func InitAuditHandler() audit.AuditHandler {
+	wire.Build(
+		PostgresSet,
+		base.NewRepo,
+		AuditRepositorySet,
+		AuditServiceSet,
+		AuditHandlerSet,
+	)
+
+	return audit.AuditHandler{}
+}
+
+func InitWellKnownHandler() wellknown.WellKnownHandler {
+	wire.Build(
+		KeySet,
+		WellKnownHandlerSet,
+	)
+
+	return wellknown.WellKnownHandler{}
+}
+
+func InitJWTVerifier() token.JWTProvider {
+	wire.Build(TokenSet)
+
+	return nil
+}
+
+func InitRevocationStore() revocation.Store {
+	wire.Build(PostgresSet, base.NewRepo, RevocationSet)
+
+	return nil
+}
+
 func ProvidePostgres() *gorm.DB {
 	wire.Build(PostgresSet)
 
@@ -8,10 +8,15 @@ package di
 
 import (
 	"github.com/geekswamp/zen/internal/base"
+	"github.com/geekswamp/zen/internal/crypto/token"
+	"github.com/geekswamp/zen/internal/handler/v1/audit"
+	"github.com/geekswamp/zen/internal/handler/v1/auth"
 	"github.com/geekswamp/zen/internal/handler/v1/user"
+	"github.com/geekswamp/zen/internal/handler/wellknown"
 	"github.com/geekswamp/zen/internal/http"
 	"github.com/geekswamp/zen/internal/repository"
 	"github.com/geekswamp/zen/internal/service"
+	"github.com/geekswamp/zen/internal/storage/revocation"
 	"gorm.io/gorm"
 )
 
@@ -21,13 +26,72 @@ func InitUserHandler() user.UserHandler {
 	baseResponse := http.New()
 	postgres := InitPostgres()
 	db := InitGorm(postgres)
+	transactor := base.NewTransactor(db)
 	baseRepository := base.NewRepo(db)
 	userRepository := repository.NewUserRepo(baseRepository)
-	userService := service.NewUserService(userRepository)
-	userHandler := user.New(baseResponse, userService)
+	refreshTokenRepository := repository.NewRefreshTokenRepo(baseRepository)
+	roleRepository := repository.NewRoleRepo(baseRepository)
+	verificationTokenRepository := repository.NewVerificationTokenRepo(baseRepository)
+	auditRepository := repository.NewAuditRepo(baseRepository)
+	store := NewRevocationStore(baseRepository)
+	mailer := InitMailer()
+	userService := service.NewUserService(transactor, userRepository, refreshTokenRepository, roleRepository, verificationTokenRepository, auditRepository, store, mailer)
+	cursorCodec := InitCursorCodec()
+	userHandler := user.New(baseResponse, userService, cursorCodec)
 	return userHandler
 }
 
+func InitAuthHandler() auth.AuthHandler {
+	baseResponse := http.New()
+	postgres := InitPostgres()
+	db := InitGorm(postgres)
+	transactor := base.NewTransactor(db)
+	baseRepository := base.NewRepo(db)
+	userRepository := repository.NewUserRepo(baseRepository)
+	refreshTokenRepository := repository.NewRefreshTokenRepo(baseRepository)
+	roleRepository := repository.NewRoleRepo(baseRepository)
+	verificationTokenRepository := repository.NewVerificationTokenRepo(baseRepository)
+	auditRepository := repository.NewAuditRepo(baseRepository)
+	store := NewRevocationStore(baseRepository)
+	mailer := InitMailer()
+	keyRing := InitKeyRing()
+	authService := service.NewAuthService(transactor, userRepository, refreshTokenRepository, roleRepository, verificationTokenRepository, auditRepository, store, mailer, keyRing)
+	authHandler := auth.New(baseResponse, authService)
+	return authHandler
+}
+
+func InitAuditHandler() audit.AuditHandler {
+	baseResponse := http.New()
+	postgres := InitPostgres()
+	db := InitGorm(postgres)
+	baseRepository := base.NewRepo(db)
+	auditRepository := repository.NewAuditRepo(baseRepository)
+	auditService := service.NewAuditService(auditRepository)
+	auditHandler := audit.New(baseResponse, auditService)
+	return auditHandler
+}
+
+func InitWellKnownHandler() wellknown.WellKnownHandler {
+	baseResponse := http.New()
+	keyRing := InitKeyRing()
+	wellKnownHandler := wellknown.New(baseResponse, keyRing)
+	return wellKnownHandler
+}
+
+func InitJWTVerifier() token.JWTProvider {
+	keyRing := InitKeyRing()
+	jwtProvider := InitVerifier(keyRing)
+	return jwtProvider
+}
+
+func InitRevocationStore() revocation.Store {
+	postgres := InitPostgres()
+	db := InitGorm(postgres)
+	baseRepository := base.NewRepo(db)
+	store := NewRevocationStore(baseRepository)
+	return store
+}
+
 func ProvidePostgres() *gorm.DB {
 	postgres := InitPostgres()
 	db := InitGorm(postgres)
@@ -20,4 +20,31 @@ var (
 	ErrValidatorTrans            = errors.New("error validator translation")
 	ErrInvalidErrCode            = errors.New("error code does not exist, please change one")
 	ErrInvalidMode               = errors.New("the 'mode' only supports 'debug' and 'release'. Please update your config file accordingly")
+	ErrInvalidCredentials        = errors.New("invalid email or password")
+	ErrUserInactive              = errors.New("user is not active")
+	ErrFailedToRehash            = errors.New("failed to rehash password")
+	ErrInvalidRefreshToken       = errors.New("invalid refresh token")
+	ErrRefreshTokenReused        = errors.New("refresh token reuse detected")
+	ErrUnsupportedAlgorithm      = errors.New("unsupported signing algorithm, use one of RS256, ES256, ES384 or EdDSA")
+	ErrKeyAlgorithmMismatch      = errors.New("key does not match the configured signing algorithm")
+	ErrInvalidRetiredKey         = errors.New("retired key 'until' must be an RFC 3339 time")
+	ErrUnknownKeyID              = errors.New("unknown or retired key id")
+	ErrRoleNotFound              = errors.New("one or more roles do not exist")
+	ErrUnsupportedMailDriver     = errors.New("the mail 'driver' only supports 'smtp' and 'log'. Please update your config file accordingly")
+	ErrFailedToSendVerification  = errors.New("failed to send verification email")
+	ErrInvalidVerificationToken  = errors.New("invalid or expired verification token")
+	ErrFailedToSendPasswordReset = errors.New("failed to send password reset email")
+	ErrWrongPassword             = errors.New("current password is incorrect")
+	ErrInvalidCursor             = errors.New("invalid or tampered pagination cursor")
+	ErrFailedToPurge             = errors.New("failed to purge soft deleted rows")
+	ErrMigrationChecksum         = errors.New("applied migration was modified, its checksum does not match")
+	ErrMigrationIrreversible     = errors.New("migration has no down step")
+	ErrMigrationUnknown          = errors.New("applied migration is missing from this build")
+	ErrInvalidMigrationFile      = errors.New("migration file name must look like 0001_name.up.sql or 0001_name.down.sql")
+	ErrDuplicateMigration        = errors.New("two migrations share the same version")
+	ErrUnknownSeeder             = errors.New("unknown seeder")
+	ErrSeederCycle               = errors.New("seeder dependencies form a cycle")
+	ErrSeederDisabled            = errors.New("seeder does not run in the active environment")
+	ErrVersionConflict           = errors.New("the row was modified concurrently, its version has moved")
+	ErrPreconditionRequired      = errors.New("the If-Match header is required")
 )
@@ -0,0 +1,70 @@
+package audit
+
+import (
+	"github.com/geekswamp/zen/internal/http"
+	"github.com/geekswamp/zen/internal/model"
+	"github.com/geekswamp/zen/internal/repository"
+	"github.com/geekswamp/zen/internal/service"
+	"github.com/geekswamp/zen/internal/validation"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AuditHandler struct {
+	resp    http.BaseResponse
+	service service.AuditService
+}
+
+func New(resp http.BaseResponse, service service.AuditService) AuditHandler {
+	return AuditHandler{resp: resp, service: service}
+}
+
+// List returns a page of audit events, newest first unless sorted ascending.
+func (h AuditHandler) List(ctx *gin.Context) {
+	query, err := validation.ValidateQuery[AuditListQuery](ctx)
+	if err != nil {
+		h.resp.Error(ctx, err)
+		return
+	}
+
+	filter := repository.AuditFilter{
+		EntityType:  query.EntityType,
+		EntityID:    query.EntityID,
+		Action:      model.AuditAction(query.Action),
+		CreatedFrom: query.CreatedFrom,
+		CreatedTo:   query.CreatedTo,
+		Descending:  query.GetSort() != http.Asc,
+	}
+
+	if query.ActorID != "" {
+		actorID, parseErr := uuid.Parse(query.ActorID)
+		if parseErr != nil {
+			h.resp.BadRequest(ctx, http.Error{Code: http.InputNotValid.Code(), Reason: "actor_id must be a valid UUID"})
+			return
+		}
+
+		filter.ActorID = &actorID
+	}
+
+	events, total, listErr := h.service.List(ctx.Request.Context(), filter, query.GetOffset(), query.GetLimit())
+	if listErr != nil {
+		h.resp.Error(ctx, listErr)
+		return
+	}
+
+	entries := make([]AuditEventResponse, 0, len(events))
+	for _, e := range events {
+		entries = append(entries, AuditEventResponse{
+			ID:          e.ID,
+			ActorID:     e.ActorID,
+			RequestID:   e.RequestID,
+			EntityType:  e.EntityType,
+			EntityID:    e.EntityID,
+			Action:      string(e.Action),
+			Changes:     e.Changes,
+			CreatedTime: e.CreatedTime,
+		})
+	}
+
+	h.resp.Success(ctx, http.NewEntries(entries, total, query.GetTotalPages(total), query.GetHasReachedMax(total)))
+}
@@ -0,0 +1,28 @@
+package audit
+
+import (
+	"github.com/geekswamp/zen/internal/http"
+	"github.com/geekswamp/zen/internal/model"
+	"github.com/google/uuid"
+)
+
+type AuditListQuery struct {
+	http.Pagination
+	EntityType  string `form:"entity_type" validate:"omitempty,max=50"`
+	EntityID    string `form:"entity_id" validate:"omitempty,max=100"`
+	ActorID     string `form:"actor_id" validate:"omitempty,uuid"`
+	Action      string `form:"action" validate:"omitempty,oneof=create update delete restore purge role_assign token_revoke password_change password_reset"`
+	CreatedFrom int64  `form:"created_from" validate:"omitempty,min=0"`
+	CreatedTo   int64  `form:"created_to" validate:"omitempty,min=0"`
+}
+
+type AuditEventResponse struct {
+	ID          uuid.UUID          `json:"id"`
+	ActorID     *uuid.UUID         `json:"actor_id"`
+	RequestID   string             `json:"request_id"`
+	EntityType  string             `json:"entity_type"`
+	EntityID    string             `json:"entity_id"`
+	Action      string             `json:"action"`
+	Changes     model.AuditChanges `json:"changes"`
+	CreatedTime int64              `json:"created_time"`
+}
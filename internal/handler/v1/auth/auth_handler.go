@@ -0,0 +1,136 @@
+package auth
+
+import (
+	"github.com/geekswamp/zen/internal/core"
+	errs "github.com/geekswamp/zen/internal/errors"
+	"github.com/geekswamp/zen/internal/http"
+	"github.com/geekswamp/zen/internal/service"
+	"github.com/geekswamp/zen/internal/validation"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const _BearerTokenType = "Bearer"
+
+type AuthHandler struct {
+	resp    http.BaseResponse
+	service service.AuthService
+}
+
+func New(resp http.BaseResponse, service service.AuthService) AuthHandler {
+	return AuthHandler{resp: resp, service: service}
+}
+
+func (h AuthHandler) Login(ctx *gin.Context) {
+	body, err := validation.ValidateBody[LoginRequest](ctx)
+	if err != nil {
+		h.resp.Error(ctx, err)
+		return
+	}
+
+	token, loginErr := h.service.Login(ctx.Request.Context(), body.Email, body.Password)
+	if loginErr != nil {
+		switch loginErr {
+		case errs.ErrInvalidCredentials:
+			h.resp.Unauthorized(ctx, http.Error{Code: http.InvalidCredentials.Code(), Reason: http.InvalidCredentials.Detail()})
+		case errs.ErrUserInactive:
+			h.resp.Unauthorized(ctx, http.Error{Code: http.UserNotActive.Code(), Reason: http.UserNotActive.Detail()})
+		default:
+			h.resp.Error(ctx, loginErr)
+		}
+		return
+	}
+
+	h.resp.Success(ctx, newTokenResponse(token))
+}
+
+func (h AuthHandler) Refresh(ctx *gin.Context) {
+	body, err := validation.ValidateBody[RefreshTokenRequest](ctx)
+	if err != nil {
+		h.resp.Error(ctx, err)
+		return
+	}
+
+	token, refreshErr := h.service.Refresh(ctx.Request.Context(), body.RefreshToken)
+	if refreshErr != nil {
+		switch refreshErr {
+		case errs.ErrInvalidRefreshToken:
+			h.resp.Unauthorized(ctx, http.Error{Code: http.InvalidRefresh.Code(), Reason: http.InvalidRefresh.Detail()})
+		case errs.ErrRefreshTokenReused:
+			h.resp.Unauthorized(ctx, http.Error{Code: http.RefreshReused.Code(), Reason: http.RefreshReused.Detail()})
+		case errs.ErrUserInactive:
+			h.resp.Unauthorized(ctx, http.Error{Code: http.UserNotActive.Code(), Reason: http.UserNotActive.Detail()})
+		default:
+			h.resp.Error(ctx, refreshErr)
+		}
+		return
+	}
+
+	h.resp.Success(ctx, newTokenResponse(token))
+}
+
+func (h AuthHandler) Logout(ctx *gin.Context) {
+	c := core.NewContext(ctx)
+
+	body, err := validation.ValidateBody[RefreshTokenRequest](ctx)
+	if err != nil {
+		h.resp.Error(ctx, err)
+		return
+	}
+
+	if err := h.service.Logout(ctx.Request.Context(), *c.GetUserSession(), body.RefreshToken); err != nil {
+		if err == errs.ErrInvalidRefreshToken {
+			h.resp.Unauthorized(ctx, http.Error{Code: http.InvalidRefresh.Code(), Reason: http.InvalidRefresh.Detail()})
+			return
+		}
+
+		h.resp.Error(ctx, err)
+		return
+	}
+
+	h.resp.Success(ctx, nil)
+}
+
+func (h AuthHandler) ForgotPassword(ctx *gin.Context) {
+	body, err := validation.ValidateBody[ForgotPasswordRequest](ctx)
+	if err != nil {
+		h.resp.Error(ctx, err)
+		return
+	}
+
+	if err := h.service.ForgotPassword(ctx.Request.Context(), body.Email); err != nil {
+		h.resp.Error(ctx, err)
+		return
+	}
+
+	h.resp.Success(ctx, nil)
+}
+
+func (h AuthHandler) ResetPassword(ctx *gin.Context) {
+	body, err := validation.ValidateBody[ResetPasswordRequest](ctx)
+	if err != nil {
+		h.resp.Error(ctx, err)
+		return
+	}
+
+	if err := h.service.ResetPassword(ctx.Request.Context(), body.Token, body.Password); err != nil {
+		switch err {
+		case errs.ErrInvalidVerificationToken, gorm.ErrRecordNotFound:
+			h.resp.BadRequest(ctx, http.Error{Code: http.InvalidResetToken.Code(), Reason: http.InvalidResetToken.Detail()})
+		default:
+			h.resp.Error(ctx, err)
+		}
+		return
+	}
+
+	h.resp.Success(ctx, nil)
+}
+
+func newTokenResponse(token *service.AuthToken) TokenResponse {
+	return TokenResponse{
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		TokenType:    _BearerTokenType,
+		ExpiresIn:    token.ExpiresIn,
+	}
+}
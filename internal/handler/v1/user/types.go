@@ -1,6 +1,9 @@
 package user
 
-import "github.com/google/uuid"
+import (
+	"github.com/geekswamp/zen/internal/http"
+	"github.com/google/uuid"
+)
 
 type UserCreateRequest struct {
 	FullName string `json:"full_name" validate:"required,min=3,max=100"`
@@ -17,6 +20,38 @@ type UserUpdateInfoRequest struct {
 	Gender   int    `json:"gender" validate:"oneof=0 1"`
 }
 
+type UserSetRolesRequest struct {
+	Roles []string `json:"roles" validate:"required,dive,required"`
+}
+
+type UserChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required,max=128"`
+	NewPassword     string `json:"new_password" validate:"required,min=8,max=128"`
+}
+
+type UserVerifyQuery struct {
+	Token string `form:"token" validate:"required"`
+}
+
+type UserFilterQuery struct {
+	Active         *bool `form:"active"`
+	Gender         *int  `form:"gender" validate:"omitempty,oneof=0 1"`
+	CreatedFrom    int64 `form:"created_from" validate:"omitempty,min=0"`
+	CreatedTo      int64 `form:"created_to" validate:"omitempty,min=0"`
+	IncludeDeleted bool  `form:"include_deleted"`
+}
+
+type UserListQuery struct {
+	http.Pagination
+	UserFilterQuery
+	SortBy string `form:"sort_by" validate:"omitempty,oneof=full_name email created_time activated_time"`
+}
+
+type UserCursorQuery struct {
+	http.CursorPagination
+	UserFilterQuery
+}
+
 type UserInfoResponse struct {
 	ID            uuid.UUID `json:"id"`
 	FullName      string    `json:"full_name"`
@@ -28,4 +63,9 @@ type UserInfoResponse struct {
 	CreatedTime   int64     `json:"created_time"`
 	UpdateTime    int64     `json:"updated_time"`
 	DeletedTime   *int64    `json:"deleted_time"`
+	Version       int64     `json:"version"`
+}
+
+func (r UserInfoResponse) GetVersion() int64 {
+	return r.Version
 }
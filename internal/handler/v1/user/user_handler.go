@@ -3,8 +3,10 @@ package user
 import (
 	"github.com/geekswamp/zen/internal/base"
 	"github.com/geekswamp/zen/internal/core"
+	errs "github.com/geekswamp/zen/internal/errors"
 	"github.com/geekswamp/zen/internal/http"
 	"github.com/geekswamp/zen/internal/model"
+	"github.com/geekswamp/zen/internal/repository"
 	"github.com/geekswamp/zen/internal/service"
 	"github.com/geekswamp/zen/internal/validation"
 	"github.com/gin-gonic/gin"
@@ -14,10 +16,11 @@ import (
 type UserHandler struct {
 	resp    http.BaseResponse
 	service service.UserService
+	cursors http.CursorCodec
 }
 
-func New(resp http.BaseResponse, service service.UserService) UserHandler {
-	return UserHandler{resp: resp, service: service}
+func New(resp http.BaseResponse, service service.UserService, cursors http.CursorCodec) UserHandler {
+	return UserHandler{resp: resp, service: service, cursors: cursors}
 }
 
 func (h UserHandler) Register(ctx *gin.Context) {
@@ -27,7 +30,7 @@ func (h UserHandler) Register(ctx *gin.Context) {
 		return
 	}
 
-	if err := h.service.Create(body.FullName, body.Email, body.Password, body.Phone, model.Gender(body.Gender)); err != nil {
+	if err := h.service.Create(ctx.Request.Context(), body.FullName, body.Email, body.Password, body.Phone, model.Gender(body.Gender)); err != nil {
 		if err == gorm.ErrDuplicatedKey {
 			h.resp.BadRequest(ctx, http.Error{Code: http.UserAlreadyExists.Code(), Reason: http.UserAlreadyExists.Detail()})
 			return
@@ -42,26 +45,13 @@ func (h UserHandler) Register(ctx *gin.Context) {
 func (h UserHandler) GetCurrent(ctx *gin.Context) {
 	c := core.NewContext(ctx)
 
-	user, err := h.service.Get(c.GetUserSession().ID)
+	user, err := h.service.Get(ctx.Request.Context(), c.GetUserSession().ID)
 	if err != nil {
 		h.resp.Error(ctx, err)
 		return
 	}
 
-	u := UserInfoResponse{
-		ID:            user.ID,
-		FullName:      user.FullName,
-		Email:         user.Email,
-		Phone:         user.Phone,
-		Active:        user.Active,
-		Gender:        int(user.Gender),
-		ActivatedTime: user.ActivatedTime,
-		CreatedTime:   user.CreatedTime,
-		UpdateTime:    user.UpdatedTime,
-		DeletedTime:   user.DeletedTime,
-	}
-
-	h.resp.Success(ctx, u)
+	h.resp.Success(ctx, newUserInfoResponse(user))
 }
 
 func (h UserHandler) GetDetail(ctx *gin.Context) {
@@ -73,7 +63,7 @@ func (h UserHandler) GetDetail(ctx *gin.Context) {
 		return
 	}
 
-	user, err := h.service.Get(ID)
+	user, err := h.service.Get(ctx.Request.Context(), ID)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			h.resp.NotFound(ctx)
@@ -84,20 +74,63 @@ func (h UserHandler) GetDetail(ctx *gin.Context) {
 		return
 	}
 
-	u := UserInfoResponse{
-		ID:            user.ID,
-		FullName:      user.FullName,
-		Email:         user.Email,
-		Phone:         user.Phone,
-		Active:        user.Active,
-		Gender:        int(user.Gender),
-		ActivatedTime: user.ActivatedTime,
-		CreatedTime:   user.CreatedTime,
-		UpdateTime:    user.UpdatedTime,
-		DeletedTime:   user.DeletedTime,
+	h.resp.Success(ctx, newUserInfoResponse(user))
+}
+
+func (h UserHandler) List(ctx *gin.Context) {
+	query, err := validation.ValidateQuery[UserListQuery](ctx)
+	if err != nil {
+		h.resp.Error(ctx, err)
+		return
+	}
+
+	filter := newUserFilter(query.Search, query.UserFilterQuery)
+	filter.SortBy = query.SortBy
+	filter.Descending = query.GetSort() != http.Asc
+
+	users, total, listErr := h.service.List(ctx.Request.Context(), filter, query.GetOffset(), query.GetLimit())
+	if listErr != nil {
+		h.resp.Error(ctx, listErr)
+		return
+	}
+
+	entries := make([]UserInfoResponse, 0, len(users))
+	for i := range users {
+		entries = append(entries, newUserInfoResponse(&users[i]))
+	}
+
+	h.resp.Success(ctx, http.NewEntries(entries, total, query.GetTotalPages(total), query.GetHasReachedMax(total)))
+}
+
+func (h UserHandler) ListByCursor(ctx *gin.Context) {
+	query, err := validation.ValidateQuery[UserCursorQuery](ctx)
+	if err != nil {
+		h.resp.Error(ctx, err)
+		return
+	}
+
+	var cursor *http.Cursor
+	if query.Cursor != "" {
+		decoded, decodeErr := h.cursors.Decode(query.Cursor)
+		if decodeErr != nil {
+			h.resp.BadRequest(ctx, http.Error{Code: http.InvalidCursor.Code(), Reason: http.InvalidCursor.Detail()})
+			return
+		}
+		cursor = decoded
+	}
+
+	result, listErr := h.service.ListByCursor(ctx.Request.Context(), newUserFilter(query.Search, query.UserFilterQuery), cursor, query.GetLimit())
+	if listErr != nil {
+		h.resp.Error(ctx, listErr)
+		return
 	}
 
-	h.resp.Success(ctx, u)
+	entries := make([]UserInfoResponse, 0, len(result.Rows))
+	for i := range result.Rows {
+		entries = append(entries, newUserInfoResponse(&result.Rows[i]))
+	}
+
+	h.resp.Success(ctx, http.NewCursorEntries(entries, h.encodeCursor(result.Next), h.encodeCursor(result.Prev)))
 }
 
 func (h UserHandler) Update(ctx *gin.Context) {
@@ -109,13 +142,26 @@ func (h UserHandler) Update(ctx *gin.Context) {
 		return
 	}
 
-	if err := h.service.Update(c.GetUserSession().ID, base.UpdateMap{
+	version, matchErr := http.IfMatch(ctx)
+	if matchErr != nil {
+		h.resp.Precondition(ctx, matchErr)
+		return
+	}
+
+	if err := h.service.Update(ctx.Request.Context(), c.GetUserSession().ID, version, base.UpdateMap{
 		"FullName": body.FullName,
 		"Email":    body.Email,
 		"Phone":    body.Phone,
 		"Gender":   body.Gender,
 	}); err != nil {
-		h.resp.Error(ctx, err)
+		switch err {
+		case gorm.ErrDuplicatedKey:
+			h.resp.BadRequest(ctx, http.Error{Code: http.UserAlreadyExists.Code(), Reason: http.UserAlreadyExists.Detail()})
+		case errs.ErrVersionConflict:
+			h.resp.PreconditionFailed(ctx)
+		default:
+			h.resp.Error(ctx, err)
+		}
 		return
 	}
 
@@ -131,7 +177,7 @@ func (h UserHandler) HardDelete(ctx *gin.Context) {
 		return
 	}
 
-	if err := h.service.Delete(ID); err != nil {
+	if err := h.service.Delete(ctx.Request.Context(), ID); err != nil {
 		if err == gorm.ErrRecordNotFound {
 			h.resp.NotFound(ctx)
 			return
@@ -153,16 +199,56 @@ func (h UserHandler) SoftDelete(ctx *gin.Context) {
 		return
 	}
 
-	if err := h.service.SoftDelete(ID); err != nil {
-		if err == gorm.ErrRecordNotFound {
+	version, err := http.IfMatch(ctx)
+	if err != nil {
+		h.resp.Precondition(ctx, err)
+		return
+	}
+
+	if err := h.service.SoftDelete(ctx.Request.Context(), ID, version); err != nil {
+		switch err {
+		case gorm.ErrRecordNotFound:
 			h.resp.NotFound(ctx)
-			return
+		case errs.ErrVersionConflict:
+			h.resp.PreconditionFailed(ctx)
+		default:
+			h.resp.Error(ctx, err)
 		}
+		return
+	}
 
+	h.resp.Success(ctx, nil)
+}
+
+func (h UserHandler) Restore(ctx *gin.Context) {
+	c := core.NewContext(ctx)
+
+	ID, err := c.ParseIDParam()
+	if err != nil {
 		h.resp.Error(ctx, err)
 		return
 	}
 
+	version, err := http.IfMatch(ctx)
+	if err != nil {
+		h.resp.Precondition(ctx, err)
+		return
+	}
+
+	if err := h.service.Restore(ctx.Request.Context(), ID, version); err != nil {
+		switch err {
+		case gorm.ErrRecordNotFound:
+			h.resp.NotFound(ctx)
+		case errs.ErrVersionConflict:
+			h.resp.PreconditionFailed(ctx)
+		case gorm.ErrDuplicatedKey:
+			h.resp.BadRequest(ctx, http.Error{Code: http.UserAlreadyExists.Code(), Reason: http.UserAlreadyExists.Detail()})
+		default:
+			h.resp.Error(ctx, err)
+		}
+		return
+	}
+
 	h.resp.Success(ctx, nil)
 }
 
@@ -175,20 +261,88 @@ func (h UserHandler) SetToActive(ctx *gin.Context) {
 		return
 	}
 
-	if err := h.service.SetToActive(ID); err != nil {
-		if err == gorm.ErrRecordNotFound {
+	version, err := http.IfMatch(ctx)
+	if err != nil {
+		h.resp.Precondition(ctx, err)
+		return
+	}
+
+	if err := h.service.SetToActive(ctx.Request.Context(), ID, version); err != nil {
+		switch err {
+		case gorm.ErrRecordNotFound:
 			h.resp.NotFound(ctx)
-			return
+		case errs.ErrVersionConflict:
+			h.resp.PreconditionFailed(ctx)
+		default:
+			h.resp.Error(ctx, err)
+		}
+		return
+	}
+
+	h.resp.Success(ctx, nil)
+}
+
+func (h UserHandler) SetToInactive(ctx *gin.Context) {
+	c := core.NewContext(ctx)
+
+	ID, err := c.ParseIDParam()
+	if err != nil {
+		h.resp.Error(ctx, err)
+		return
+	}
+
+	version, err := http.IfMatch(ctx)
+	if err != nil {
+		h.resp.Precondition(ctx, err)
+		return
+	}
+
+	if err := h.service.SetToInactive(ctx.Request.Context(), ID, version); err != nil {
+		switch err {
+		case gorm.ErrRecordNotFound:
+			h.resp.NotFound(ctx)
+		case errs.ErrVersionConflict:
+			h.resp.PreconditionFailed(ctx)
+		default:
+			h.resp.Error(ctx, err)
 		}
+		return
+	}
 
+	h.resp.Success(ctx, nil)
+}
+
+func (h UserHandler) RevokeTokens(ctx *gin.Context) {
+	c := core.NewContext(ctx)
+
+	ID, err := c.ParseIDParam()
+	if err != nil {
 		h.resp.Error(ctx, err)
 		return
 	}
 
+	version, err := http.IfMatch(ctx)
+	if err != nil {
+		h.resp.Precondition(ctx, err)
+		return
+	}
+
+	if err := h.service.RevokeTokens(ctx.Request.Context(), ID, version); err != nil {
+		switch err {
+		case gorm.ErrRecordNotFound:
+			h.resp.NotFound(ctx)
+		case errs.ErrVersionConflict:
+			h.resp.PreconditionFailed(ctx)
+		default:
+			h.resp.Error(ctx, err)
+		}
+		return
+	}
+
 	h.resp.Success(ctx, nil)
 }
 
-func (h UserHandler) SetToInactive(ctx *gin.Context) {
+func (h UserHandler) SetRoles(ctx *gin.Context) {
 	c := core.NewContext(ctx)
 
 	ID, err := c.ParseIDParam()
@@ -197,15 +351,116 @@ func (h UserHandler) SetToInactive(ctx *gin.Context) {
 		return
 	}
 
-	if err := h.service.SetToInactive(ID); err != nil {
-		if err == gorm.ErrRecordNotFound {
+	body, validationErr := validation.ValidateBody[UserSetRolesRequest](ctx)
+	if validationErr != nil {
+		h.resp.Error(ctx, validationErr)
+		return
+	}
+
+	version, err := http.IfMatch(ctx)
+	if err != nil {
+		h.resp.Precondition(ctx, err)
+		return
+	}
+
+	if err := h.service.SetRoles(ctx.Request.Context(), ID, version, body.Roles); err != nil {
+		switch err {
+		case gorm.ErrRecordNotFound:
 			h.resp.NotFound(ctx)
-			return
+		case errs.ErrRoleNotFound:
+			h.resp.BadRequest(ctx, http.Error{Code: http.RoleNotFound.Code(), Reason: http.RoleNotFound.Detail()})
+		case errs.ErrVersionConflict:
+			h.resp.PreconditionFailed(ctx)
+		default:
+			h.resp.Error(ctx, err)
+		}
+		return
+	}
+
+	h.resp.Success(ctx, nil)
+}
+
+func (h UserHandler) Verify(ctx *gin.Context) {
+	query, err := validation.ValidateQuery[UserVerifyQuery](ctx)
+	if err != nil {
+		h.resp.Error(ctx, err)
+		return
+	}
+
+	if err := h.service.VerifyEmail(ctx.Request.Context(), query.Token); err != nil {
+		switch err {
+		case errs.ErrInvalidVerificationToken, gorm.ErrRecordNotFound:
+			h.resp.BadRequest(ctx, http.Error{Code: http.InvalidVerifyToken.Code(), Reason: http.InvalidVerifyToken.Detail()})
+		default:
+			h.resp.Error(ctx, err)
 		}
+		return
+	}
 
+	h.resp.Success(ctx, nil)
+}
+
+func (h UserHandler) ChangePassword(ctx *gin.Context) {
+	c := core.NewContext(ctx)
+
+	body, err := validation.ValidateBody[UserChangePasswordRequest](ctx)
+	if err != nil {
 		h.resp.Error(ctx, err)
 		return
 	}
 
+	if err := h.service.ChangePassword(ctx.Request.Context(), c.GetUserSession().ID, body.CurrentPassword, body.NewPassword); err != nil {
+		switch err {
+		case errs.ErrWrongPassword:
+			h.resp.BadRequest(ctx, http.Error{Code: http.WrongPassword.Code(), Reason: http.WrongPassword.Detail()})
+		case gorm.ErrRecordNotFound:
+			h.resp.NotFound(ctx)
+		default:
+			h.resp.Error(ctx, err)
+		}
+		return
+	}
+
 	h.resp.Success(ctx, nil)
 }
+
+func newUserInfoResponse(user *model.User) UserInfoResponse {
+	return UserInfoResponse{
+		ID:            user.ID,
+		FullName:      user.FullName,
+		Email:         user.Email,
+		Phone:         user.Phone,
+		Active:        user.Active,
+		Gender:        int(user.Gender),
+		ActivatedTime: user.ActivatedTime,
+		CreatedTime:   user.CreatedTime,
+		UpdateTime:    user.UpdatedTime,
+		DeletedTime:   user.DeletedTime.Millis(),
+		Version:       user.Version,
+	}
+}
+
+func newUserFilter(search string, query UserFilterQuery) repository.UserFilter {
+	filter := repository.UserFilter{
+		Search:         search,
+		Active:         query.Active,
+		CreatedFrom:    query.CreatedFrom,
+		CreatedTo:      query.CreatedTo,
+		IncludeDeleted: query.IncludeDeleted,
+	}
+
+	if query.Gender != nil {
+		gender := model.Gender(*query.Gender)
+		filter.Gender = &gender
+	}
+
+	return filter
+}
+
+func (h UserHandler) encodeCursor(cursor *http.Cursor) string {
+	if cursor == nil {
+		return ""
+	}
+
+	return h.cursors.Encode(*cursor)
+}
@@ -0,0 +1,34 @@
+package wellknown
+
+import (
+	nethttp "net/http"
+
+	"github.com/geekswamp/zen/internal/crypto/key"
+	"github.com/geekswamp/zen/internal/http"
+	"github.com/gin-gonic/gin"
+)
+
+const _JWKSCacheControl = "public, max-age=300"
+
+type WellKnownHandler struct {
+	resp http.BaseResponse
+	keys key.KeyRing
+}
+
+func New(resp http.BaseResponse, keys key.KeyRing) WellKnownHandler {
+	return WellKnownHandler{resp: resp, keys: keys}
+}
+
+// JWKS serves the public keys trusted for token verification. The set is written as a
+// bare JWK Set document instead of the usual response envelope, since that is what
+// JWT libraries of other services expect.
+func (h WellKnownHandler) JWKS(ctx *gin.Context) {
+	set, err := key.NewJWKSet(h.keys)
+	if err != nil {
+		h.resp.Error(ctx, err)
+		return
+	}
+
+	ctx.Header("Cache-Control", _JWKSCacheControl)
+	ctx.JSON(nethttp.StatusOK, set)
+}
@@ -8,6 +8,22 @@ var (
 	NotValidQuery      = NewErrorCode("ERR-PA40004", "The provided URL Query is not valid")
 	UserAlreadyExists  = NewErrorCode("ERR-PA40005", "User already exists. Please use a different email or phone number")
 	NotFound           = NewErrorCode("ERR-PA40006", "The requested resource was not found")
+	RoleNotFound       = NewErrorCode("ERR-PA40007", "One or more of the given roles do not exist")
+	InvalidVerifyToken = NewErrorCode("ERR-PA40008", "The verification token is invalid or has expired")
+	InvalidResetToken  = NewErrorCode("ERR-PA40009", "The password reset token is invalid or has expired")
+	WrongPassword      = NewErrorCode("ERR-PA40010", "The current password is incorrect")
+	InvalidCursor      = NewErrorCode("ERR-PA40011", "The pagination cursor is invalid")
+	VersionConflict    = NewErrorCode("ERR-PA41201", "The resource was modified by someone else. Fetch it again and retry with its current ETag")
+	IfMatchRequired    = NewErrorCode("ERR-PA42801", "Missing If-Match header. Send the ETag of the resource to modify it")
 	InvalidRequestID   = NewErrorCode("ERR-HR40001", "Invalid X-Request-ID format. It must be a valid UUID")
+	InvalidAuthHeader  = NewErrorCode("ERR-HR40002", "Missing or invalid Authorization header. It must use the Bearer scheme")
+	InvalidCredentials = NewErrorCode("ERR-AU40101", "Invalid email or password")
+	InvalidToken       = NewErrorCode("ERR-AU40102", "The access token is invalid")
+	TokenExpired       = NewErrorCode("ERR-AU40103", "The access token has expired")
+	UserNotActive      = NewErrorCode("ERR-AU40104", "User account is not active")
+	InvalidRefresh     = NewErrorCode("ERR-AU40105", "The refresh token is invalid or has expired")
+	RefreshReused      = NewErrorCode("ERR-AU40106", "The refresh token has already been used. All sessions of this login have been revoked")
+	TokenRevoked       = NewErrorCode("ERR-AU40107", "The access token has been revoked")
 	SystemError        = NewErrorCode("ERR-SY50001", "A system error has occurred, please try again later")
+	RequestTimeout     = NewErrorCode("ERR-SY50301", "The request took too long to complete, please try again later")
 )
@@ -0,0 +1,64 @@
+package http_test
+
+import (
+	"testing"
+
+	"github.com/geekswamp/zen/internal/errors"
+	"github.com/geekswamp/zen/internal/http"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursorCodec(t *testing.T) {
+	codec := http.NewCursorCodec([]byte("secret"))
+	cursor := http.Cursor{CreatedTime: 1700000000000, ID: uuid.New(), Direction: http.Next}
+	encoded := codec.Encode(cursor)
+
+	testCases := []struct {
+		name    string
+		codec   http.CursorCodec
+		encoded string
+		wantErr error
+	}{
+		{
+			name:    "Valid Cursor",
+			codec:   codec,
+			encoded: encoded,
+			wantErr: nil,
+		},
+		{
+			name:    "Wrong Secret",
+			codec:   http.NewCursorCodec([]byte("other")),
+			encoded: encoded,
+			wantErr: errors.ErrInvalidCursor,
+		},
+		{
+			name:    "Tampered Payload",
+			codec:   codec,
+			encoded: "x" + encoded,
+			wantErr: errors.ErrInvalidCursor,
+		},
+		{
+			name:    "Missing Signature",
+			codec:   codec,
+			encoded: "eyJ0IjoxfQ",
+			wantErr: errors.ErrInvalidCursor,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			decoded, err := tc.codec.Decode(tc.encoded)
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.Nil(t, decoded)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, cursor, *decoded)
+		})
+	}
+}
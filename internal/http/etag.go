@@ -0,0 +1,53 @@
+package http
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/geekswamp/zen/internal/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// Versioned is implemented by responses of a single resource with a row version.
+// Success sends the version of such responses as ETag header.
+type Versioned interface {
+	GetVersion() int64
+}
+
+// ETag formats a row version as strong entity tag.
+func ETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// IfMatch returns the row version required by the If-Match header of the request.
+// It returns 0, which matches any version, when the header is "*", and
+// errors.ErrPreconditionRequired when it is missing, so that every change states the
+// version it was based on. Weak, malformed or multiple tags can never match and return
+// errors.ErrVersionConflict.
+func IfMatch(c *gin.Context) (int64, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		return 0, errors.ErrPreconditionRequired
+	}
+
+	if header == "*" {
+		return 0, nil
+	}
+
+	unquoted, ok := strings.CutPrefix(header, `"`)
+	if !ok {
+		return 0, errors.ErrVersionConflict
+	}
+
+	unquoted, ok = strings.CutSuffix(unquoted, `"`)
+	if !ok {
+		return 0, errors.ErrVersionConflict
+	}
+
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version < 1 {
+		return 0, errors.ErrVersionConflict
+	}
+
+	return version, nil
+}
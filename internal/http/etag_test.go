@@ -0,0 +1,90 @@
+package http_test
+
+import (
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/geekswamp/zen/internal/core"
+	"github.com/geekswamp/zen/internal/errors"
+	"github.com/geekswamp/zen/internal/http"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type versioned struct {
+	Version int64 `json:"version"`
+}
+
+func (v versioned) GetVersion() int64 {
+	return v.Version
+}
+
+func TestIfMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		name        string
+		header      string
+		wantVersion int64
+		wantErr     error
+	}{
+		{name: "Missing Header", header: "", wantErr: errors.ErrPreconditionRequired},
+		{name: "Any Version", header: "*", wantVersion: 0},
+		{name: "Strong Tag", header: `"7"`, wantVersion: 7},
+		{name: "Weak Tag", header: `W/"7"`, wantErr: errors.ErrVersionConflict},
+		{name: "Unquoted Tag", header: "7", wantErr: errors.ErrVersionConflict},
+		{name: "Multiple Tags", header: `"7", "8"`, wantErr: errors.ErrVersionConflict},
+		{name: "Not A Version", header: `"abc"`, wantErr: errors.ErrVersionConflict},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(nethttp.MethodPut, "/", nil)
+			if tc.header != "" {
+				c.Request.Header.Set("If-Match", tc.header)
+			}
+
+			version, err := http.IfMatch(c)
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantVersion, version)
+		})
+	}
+}
+
+func TestSuccessETag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		name     string
+		data     any
+		wantETag string
+	}{
+		{name: "Versioned Data", data: versioned{Version: 3}, wantETag: `"3"`},
+		{name: "Plain Data", data: map[string]int{"a": 1}, wantETag: ""},
+		{name: "Entries", data: http.NewEntries([]versioned{{Version: 3}}, 1, 1, true), wantETag: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			ctx := core.NewContext(c)
+			ctx.SetRequestID(uuid.New())
+
+			http.New().Success(c, tc.data)
+
+			assert.Equal(t, nethttp.StatusOK, w.Code)
+			assert.Equal(t, tc.wantETag, w.Header().Get("ETag"))
+		})
+	}
+}
@@ -16,10 +16,10 @@ const (
 
 // Pagination defines the query parameters structure for pagination and sorting.
 type Pagination struct {
-	Page   int64      `form:"page" validate:"omitempty"`
-	Limit  int64      `form:"limit" validate:"omitempty"`
-	Sort   SortOption `form:"sort" validate:"omitempty"`
-	Search string     `form:"search" validate:"omitempty"`
+	Page   int64      `form:"page" validate:"omitempty,min=1"`
+	Limit  int64      `form:"limit" validate:"omitempty,min=1,max=100"`
+	Sort   SortOption `form:"sort" validate:"omitempty,oneof=asc desc"`
+	Search string     `form:"search" validate:"omitempty,max=100"`
 }
 
 // GetPage returns the current page number from the query parameters.
@@ -52,7 +52,7 @@ func (p *Pagination) GetSort() SortOption {
 
 // GetTotalPages calculates the total number of pages based on the total number of items and the limit per page.
 func (p *Pagination) GetTotalPages(totalItems int64) int64 {
-	return int64(math.Ceil(float64(totalItems) / float64(p.Limit)))
+	return int64(math.Ceil(float64(totalItems) / float64(p.GetLimit())))
 }
 
 // GetHasReachedMax checks if the current page has reached the maximum number of pages.
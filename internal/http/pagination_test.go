@@ -0,0 +1,52 @@
+package http_test
+
+import (
+	"testing"
+
+	"github.com/geekswamp/zen/internal/http"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPagination(t *testing.T) {
+	testCases := []struct {
+		name              string
+		pagination        http.Pagination
+		totalItems        int64
+		wantOffset        int64
+		wantTotalPages    int64
+		wantHasReachedMax bool
+	}{
+		{
+			name:              "Defaults",
+			pagination:        http.Pagination{},
+			totalItems:        25,
+			wantOffset:        0,
+			wantTotalPages:    3,
+			wantHasReachedMax: false,
+		},
+		{
+			name:              "Last Page",
+			pagination:        http.Pagination{Page: 3, Limit: 10},
+			totalItems:        25,
+			wantOffset:        20,
+			wantTotalPages:    3,
+			wantHasReachedMax: true,
+		},
+		{
+			name:              "No Items",
+			pagination:        http.Pagination{Limit: 20},
+			totalItems:        0,
+			wantOffset:        0,
+			wantTotalPages:    0,
+			wantHasReachedMax: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.wantOffset, tc.pagination.GetOffset())
+			assert.Equal(t, tc.wantTotalPages, tc.pagination.GetTotalPages(tc.totalItems))
+			assert.Equal(t, tc.wantHasReachedMax, tc.pagination.GetHasReachedMax(tc.totalItems))
+		})
+	}
+}
@@ -1,10 +1,13 @@
 package http
 
 import (
+	"context"
+	"errors"
 	"io"
 	"net/http"
 
 	"github.com/geekswamp/zen/internal/core"
+	errs "github.com/geekswamp/zen/internal/errors"
 	"github.com/gin-gonic/gin"
 )
 
@@ -32,6 +35,14 @@ type Entries[T any] struct {
 	HasReachedMax bool  `json:"has_reached_max"`
 }
 
+// CursorEntries represents a collection of items of type T paginated with cursors.
+// A cursor is omitted when there is no page in its direction.
+type CursorEntries[T any] struct {
+	Entries    []T    `json:"entries"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
 // New creates and returns a new empty instance of BaseResponse.
 // This function serves as a constructor for initializing a BaseResponse object
 // with its zero values.
@@ -54,13 +65,27 @@ func NewEntries[T any](entries []T, totalItems, totalPages int64, hasReachedMax
 	}
 }
 
+// NewCursorEntries creates a new CursorEntries instance from the entries and the encoded cursors.
+func NewCursorEntries[T any](entries []T, nextCursor, prevCursor string) CursorEntries[T] {
+	return CursorEntries[T]{
+		Entries:    entries,
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
+	}
+}
+
 // Created sends a JSON response with HTTP 201 Created status code.
 func (b BaseResponse) Created(c *gin.Context, data any) {
 	newResponse(c, http.StatusCreated, nil, data)
 }
 
 // Success sends a JSON response with HTTP 200 Ok status code.
+// Versioned data is sent with its version as ETag header.
 func (b BaseResponse) Success(c *gin.Context, data any) {
+	if v, ok := data.(Versioned); ok {
+		c.Header("ETag", ETag(v.GetVersion()))
+	}
+
 	newResponse(c, http.StatusOK, nil, data)
 }
 
@@ -74,6 +99,33 @@ func (b BaseResponse) Unauthorized(c *gin.Context, err Error) {
 	newResponse(c, http.StatusUnauthorized, &err, nil)
 }
 
+// Forbidden sends a JSON response with HTTP 403 Forbidden status code.
+func (b BaseResponse) Forbidden(c *gin.Context) {
+	newResponse(c, http.StatusForbidden, &Error{Code: Forbidden.Code(), Reason: Forbidden.Detail()}, nil)
+}
+
+// PreconditionFailed sends a JSON response with HTTP 412 Precondition Failed status code,
+// used when the If-Match header does not match the current version of a resource.
+func (b BaseResponse) PreconditionFailed(c *gin.Context) {
+	newResponse(c, http.StatusPreconditionFailed, &Error{Code: VersionConflict.Code(), Reason: VersionConflict.Detail()}, nil)
+}
+
+// PreconditionRequired sends a JSON response with HTTP 428 Precondition Required status code,
+// used when a request modifying a resource has no If-Match header.
+func (b BaseResponse) PreconditionRequired(c *gin.Context) {
+	newResponse(c, http.StatusPreconditionRequired, &Error{Code: IfMatchRequired.Code(), Reason: IfMatchRequired.Detail()}, nil)
+}
+
+// Precondition responds to an error of IfMatch: 428 when the header is missing, 412 otherwise.
+func (b BaseResponse) Precondition(c *gin.Context, err error) {
+	if err == errs.ErrPreconditionRequired {
+		b.PreconditionRequired(c)
+		return
+	}
+
+	b.PreconditionFailed(c)
+}
+
 // TMR sends a JSON response with HTTP 429 Too Many Requests status code.
 func (b BaseResponse) TMR(c *gin.Context) {
 	newResponse(c, http.StatusTooManyRequests, &Error{Code: TooManyReqs.Code(), Reason: TooManyReqs.Detail()}, nil)
@@ -92,7 +144,8 @@ func (b BaseResponse) NotFound(c *gin.Context) {
 // Error handles and formats error responses for HTTP requests.
 // It accepts a gin.Context and any error parameter, processing different error types:
 //   - For custom Error type: Responds with BadRequest
-//   - For standard error type: Processes specific cases like io.EOF with appropriate status codes
+//   - For standard error type: Processes specific cases like io.EOF with appropriate status codes,
+//     and context.DeadlineExceeded from a request timeout with 503 Service Unavailable
 func (b BaseResponse) Error(c *gin.Context, errParam any) {
 	switch err := errParam.(type) {
 	case *Error:
@@ -103,6 +156,11 @@ func (b BaseResponse) Error(c *gin.Context, errParam any) {
 			return
 		}
 
+		if errors.Is(err, context.DeadlineExceeded) {
+			newResponse(c, http.StatusServiceUnavailable, &Error{Code: RequestTimeout.Code(), Reason: RequestTimeout.Detail()}, nil)
+			return
+		}
+
 		newResponse(c, http.StatusInternalServerError, &Error{Code: SystemError.Code(), Reason: SystemError.Detail()}, nil)
 	}
 }
@@ -0,0 +1,104 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/geekswamp/zen/internal/errors"
+	"github.com/geekswamp/zen/internal/logger"
+	"github.com/geekswamp/zen/internal/model"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var log = logger.New()
+
+// _PurgeLockKey identifies the Postgres advisory lock held while purging, so that only one
+// replica purges at a time.
+const _PurgeLockKey int64 = 7_236_842_119_046_502_402
+
+// expiringModels lists the revocation tables whose rows are useless once expires_time has passed.
+var expiringModels = []any{&model.RevokedToken{}, &model.UserRevocation{}}
+
+// Purge hard-deletes rows that have been soft deleted for longer than the retention period.
+type Purge struct {
+	db        *gorm.DB
+	retention time.Duration
+	interval  time.Duration
+	models    []any
+}
+
+// NewPurge creates a purge job for the given models, which must embed base.Model.
+func NewPurge(db *gorm.DB, retention, interval time.Duration, models ...any) *Purge {
+	return &Purge{db: db, retention: retention, interval: interval, models: models}
+}
+
+// Run purges once at start and then at every interval until the context is done.
+// It returns at once when the retention or interval is not positive.
+func (p *Purge) Run(ctx context.Context) {
+	if p.retention <= 0 || p.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.PurgeOnce(ctx); err != nil {
+			log.Error(errors.ErrFailedToPurge.Error(), logger.ErrDetails(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeOnce hard-deletes the rows of every model soft deleted before the retention cutoff,
+// along with the expired token revocations.
+// It does nothing while another replica holds the purge advisory lock.
+func (p *Purge) PurgeOnce(ctx context.Context) error {
+	return p.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		// Connection hands over a single statement; start a new one for every query on the pinned conn.
+		conn = conn.Session(&gorm.Session{NewDB: true})
+
+		var locked bool
+		if err := conn.Raw("SELECT pg_try_advisory_lock(?)", _PurgeLockKey).Scan(&locked).Error; err != nil {
+			return err
+		}
+
+		if !locked {
+			return nil
+		}
+		defer conn.Exec("SELECT pg_advisory_unlock(?)", _PurgeLockKey)
+
+		now := conn.NowFunc()
+		cutoff := now.Add(-p.retention).UnixMilli()
+
+		for _, model := range p.models {
+			qr := conn.Unscoped().Where("deleted_time < ?", cutoff).Delete(model)
+			if qr.Error != nil {
+				return qr.Error
+			}
+
+			if qr.RowsAffected > 0 {
+				log.Info("Purged soft deleted rows", zap.String("table", qr.Statement.Table), zap.Int64("rows", qr.RowsAffected))
+			}
+		}
+
+		for _, model := range expiringModels {
+			qr := conn.Where("expires_time < ?", now.UnixMilli()).Delete(model)
+			if qr.Error != nil {
+				return qr.Error
+			}
+
+			if qr.RowsAffected > 0 {
+				log.Info("Purged expired rows", zap.String("table", qr.Statement.Table), zap.Int64("rows", qr.RowsAffected))
+			}
+		}
+
+		return nil
+	})
+}
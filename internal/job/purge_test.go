@@ -0,0 +1,56 @@
+package job_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/geekswamp/zen/internal/job"
+	"github.com/geekswamp/zen/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestPurgeOnce(t *testing.T) {
+	testCases := []struct {
+		name   string
+		expect func(m sqlmock.Sqlmock)
+	}{
+		{
+			name: "Lock Acquired",
+			expect: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(`SELECT pg_try_advisory_lock`).WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(true))
+				m.ExpectExec(`DELETE FROM "users" WHERE deleted_time < \$1$`).WillReturnResult(sqlmock.NewResult(0, 2))
+				m.ExpectExec(`DELETE FROM "roles" WHERE deleted_time < \$1$`).WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectExec(`DELETE FROM "revoked_tokens" WHERE expires_time < \$1$`).WillReturnResult(sqlmock.NewResult(0, 3))
+				m.ExpectExec(`DELETE FROM "user_revocations" WHERE expires_time < \$1$`).WillReturnResult(sqlmock.NewResult(0, 1))
+				m.ExpectExec(`SELECT pg_advisory_unlock`).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "Lock Held Elsewhere",
+			expect: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(`SELECT pg_try_advisory_lock`).WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(false))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			conn, sqlMock, err := sqlmock.New()
+			require.NoError(t, err)
+			t.Cleanup(func() { conn.Close() })
+
+			db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{SkipDefaultTransaction: true})
+			require.NoError(t, err)
+
+			tc.expect(sqlMock)
+
+			err = job.NewPurge(db, time.Hour, time.Hour, &model.User{}, &model.Role{}).PurgeOnce(t.Context())
+			assert.NoError(t, err)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
@@ -13,6 +13,8 @@ const (
 	_LocalKey            string = "local"
 	_ErrDetailsKey       string = "error_details"
 	_ServerDetailsKey    string = "server_details"
+	_MailRecipientKey    string = "mail_recipient"
+	_RequestIDKey        string = "request_id"
 )
 
 func id() string {
@@ -38,3 +40,11 @@ func ErrDetails(err error) zapcore.Field {
 func Server(addr string) zapcore.Field {
 	return zap.String(_ServerDetailsKey, addr)
 }
+
+func MailRecipient(to string) zapcore.Field {
+	return zap.String(_MailRecipientKey, to)
+}
+
+func RequestID(id string) zapcore.Field {
+	return zap.String(_RequestIDKey, id)
+}
@@ -0,0 +1,37 @@
+package mail
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/geekswamp/zen/internal/logger"
+	"go.uber.org/zap"
+)
+
+// Log is a mailer for local development. Messages are written as .eml files to dir,
+// or to the application log when dir is empty. Nothing is delivered.
+type Log struct {
+	from string
+	dir  string
+}
+
+func NewLog(from, dir string) Mailer {
+	return Log{from: from, dir: dir}
+}
+
+func (l Log) Send(msg Message) error {
+	if l.dir == "" {
+		log.Info(msg.Subject, logger.MailRecipient(msg.To), zap.String("body", msg.Body))
+		return nil
+	}
+
+	if err := os.MkdirAll(l.dir, 0o755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), strings.NewReplacer("@", "_at_", "/", "_").Replace(msg.To))
+	return os.WriteFile(filepath.Join(l.dir, name), encode(l.from, msg), 0o600)
+}
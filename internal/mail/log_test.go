@@ -0,0 +1,31 @@
+package mail_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/geekswamp/zen/internal/mail"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogSend(t *testing.T) {
+	dir := t.TempDir()
+	mailer := mail.NewLog("Zen <no-reply@zen.local>", dir)
+
+	err := mailer.Send(mail.Message{To: "john@doe.com", Subject: "Verify your email", Body: "https://zen.local/verify?token=abc"})
+	require.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Contains(t, files[0], "john_at_doe.com")
+
+	content, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "From: Zen <no-reply@zen.local>\r\n")
+	assert.Contains(t, string(content), "To: john@doe.com\r\n")
+	assert.Contains(t, string(content), "Subject: Verify your email\r\n")
+	assert.Contains(t, string(content), "\r\n\r\nhttps://zen.local/verify?token=abc")
+}
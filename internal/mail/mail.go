@@ -0,0 +1,60 @@
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"time"
+
+	"github.com/geekswamp/zen/configs"
+	"github.com/geekswamp/zen/internal/errors"
+	"github.com/geekswamp/zen/internal/logger"
+)
+
+var log = logger.New()
+
+const (
+	DriverSMTP = "smtp"
+	DriverLog  = "log"
+)
+
+// Message is a plain text email sent to a single recipient.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers messages. Implementations must be safe for concurrent use.
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// New creates the mailer selected by the mail driver in the configuration.
+func New() (Mailer, error) {
+	cfg := configs.Get().Mail
+
+	switch cfg.Driver {
+	case DriverSMTP:
+		return NewSMTP(cfg.From, cfg.SMTP.Host, cfg.SMTP.Port, cfg.SMTP.Username, cfg.SMTP.Password), nil
+	case DriverLog:
+		return NewLog(cfg.From, cfg.Dir), nil
+	default:
+		return nil, errors.ErrUnsupportedMailDriver
+	}
+}
+
+// encode renders the message as an RFC 5322 document.
+func encode(from string, msg Message) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(msg.Body)
+
+	return b.Bytes()
+}
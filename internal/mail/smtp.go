@@ -0,0 +1,41 @@
+package mail
+
+import (
+	"fmt"
+	"net/mail"
+	"net/smtp"
+)
+
+// SMTP sends messages through an SMTP server. PLAIN authentication is used when
+// a username is set, which net/smtp only allows over TLS or to localhost.
+type SMTP struct {
+	from     string
+	addr     string
+	host     string
+	username string
+	password string
+}
+
+func NewSMTP(from, host string, port uint32, username, password string) Mailer {
+	return SMTP{
+		from:     from,
+		addr:     fmt.Sprintf("%s:%d", host, port),
+		host:     host,
+		username: username,
+		password: password,
+	}
+}
+
+func (s SMTP) Send(msg Message) error {
+	sender, err := mail.ParseAddress(s.from)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if s.username != "" {
+		auth = smtp.PlainAuth("", s.username, s.password, s.host)
+	}
+
+	return smtp.SendMail(s.addr, auth, sender.Address, []string{msg.To}, encode(s.from, msg))
+}
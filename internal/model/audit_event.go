@@ -0,0 +1,105 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+type AuditAction string
+
+const (
+	AuditCreate  AuditAction = "create"
+	AuditUpdate  AuditAction = "update"
+	AuditDelete  AuditAction = "delete"
+	AuditRestore AuditAction = "restore"
+	AuditPurge   AuditAction = "purge"
+
+	// Actions recorded explicitly by the services, for changes that leave no diff on an
+	// Auditable row.
+	AuditRoleAssign     AuditAction = "role_assign"
+	AuditTokenRevoke    AuditAction = "token_revoke"
+	AuditPasswordChange AuditAction = "password_change"
+	AuditPasswordReset  AuditAction = "password_reset"
+)
+
+// Auditable is implemented by models whose mutations are recorded as audit events.
+// AuditType names the entity type of the events.
+type Auditable interface {
+	AuditType() string
+}
+
+// auditIgnored lists the columns that change with every write and are left out of diffs.
+var auditIgnored = []string{"updated_time", "version"}
+
+// AuditChange holds the value of a column before and after a mutation.
+type AuditChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// AuditChanges maps column names to their changes. It is stored as JSON.
+type AuditChanges map[string]AuditChange
+
+// Scan implements the Scanner interface.
+func (a *AuditChanges) Scan(value any) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, a)
+	case string:
+		return json.Unmarshal([]byte(v), a)
+	case nil:
+		*a = nil
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into AuditChanges", value)
+	}
+}
+
+// Value implements the driver Valuer interface.
+func (a AuditChanges) Value() (driver.Value, error) {
+	b, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+// DiffAudit returns the columns whose values differ between two versions of a row.
+// A nil before or after row stands for a row that did not exist, so every column is included.
+func DiffAudit(before, after map[string]any) AuditChanges {
+	changes := AuditChanges{}
+	for _, row := range []map[string]any{before, after} {
+		for column := range row {
+			if _, ok := changes[column]; ok || slices.Contains(auditIgnored, column) {
+				continue
+			}
+
+			if before != nil && after != nil && reflect.DeepEqual(before[column], after[column]) {
+				continue
+			}
+
+			changes[column] = AuditChange{Before: before[column], After: after[column]}
+		}
+	}
+
+	return changes
+}
+
+// AuditEvent records a mutation of an Auditable entity, who made it and within which request.
+// Events are append-only and written in the transaction of the mutation.
+type AuditEvent struct {
+	ID          uuid.UUID    `gorm:"column:id;primaryKey;type:uuid;default:uuid_generate_v4()"`
+	ActorID     *uuid.UUID   `gorm:"column:actor_id;type:uuid;index"`
+	RequestID   string       `gorm:"column:request_id;type:varchar"`
+	EntityType  string       `gorm:"column:entity_type;type:varchar;not null;index:idx_audit_events_entity,priority:1"`
+	EntityID    string       `gorm:"column:entity_id;type:varchar;not null;index:idx_audit_events_entity,priority:2"`
+	Action      AuditAction  `gorm:"column:action;type:varchar;not null"`
+	Changes     AuditChanges `gorm:"column:changes;type:jsonb;not null"`
+	CreatedTime int64        `gorm:"column:created_time;autoCreateTime:milli;index"`
+}
@@ -0,0 +1,73 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/geekswamp/zen/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffAudit(t *testing.T) {
+	row := map[string]any{"id": "1", "full_name": "John", "active": true, "version": int64(1), "updated_time": int64(10)}
+
+	testCases := []struct {
+		name   string
+		before map[string]any
+		after  map[string]any
+		want   model.AuditChanges
+	}{
+		{
+			name:   "Created",
+			before: nil,
+			after:  row,
+			want: model.AuditChanges{
+				"id":        {Before: nil, After: "1"},
+				"full_name": {Before: nil, After: "John"},
+				"active":    {Before: nil, After: true},
+			},
+		},
+		{
+			name:   "Updated",
+			before: row,
+			after:  map[string]any{"id": "1", "full_name": "John", "active": false, "version": int64(2), "updated_time": int64(20)},
+			want: model.AuditChanges{
+				"active": {Before: true, After: false},
+			},
+		},
+		{
+			name:   "Unchanged",
+			before: row,
+			after:  map[string]any{"id": "1", "full_name": "John", "active": true, "version": int64(2), "updated_time": int64(20)},
+			want:   model.AuditChanges{},
+		},
+		{
+			name:   "Purged",
+			before: row,
+			after:  nil,
+			want: model.AuditChanges{
+				"id":        {Before: "1", After: nil},
+				"full_name": {Before: "John", After: nil},
+				"active":    {Before: true, After: nil},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, model.DiffAudit(tc.before, tc.after))
+		})
+	}
+}
+
+func TestAuditChangesValue(t *testing.T) {
+	changes := model.AuditChanges{"active": {Before: true, After: false}}
+
+	value, err := changes.Value()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"active":{"before":true,"after":false}}`, value.(string))
+
+	var scanned model.AuditChanges
+	require.NoError(t, scanned.Scan([]byte(value.(string))))
+	assert.Equal(t, changes, scanned)
+}
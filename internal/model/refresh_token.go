@@ -0,0 +1,18 @@
+package model
+
+import (
+	"github.com/geekswamp/zen/internal/base"
+	"github.com/google/uuid"
+)
+
+// RefreshToken is a persisted, single-use refresh token. Tokens issued by rotating
+// each other share the same FamilyID so that a whole chain can be revoked at once.
+type RefreshToken struct {
+	base.Model  `gorm:"embedded"`
+	UserID      uuid.UUID `gorm:"column:user_id;type:uuid;index;not null"`
+	FamilyID    uuid.UUID `gorm:"column:family_id;type:uuid;index;not null"`
+	TokenHash   string    `gorm:"column:token_hash;type:varchar;uniqueIndex;not null"`
+	ExpiresTime int64     `gorm:"column:expires_time;not null"`
+	UsedTime    *int64    `gorm:"column:used_time"`
+	RevokedTime *int64    `gorm:"column:revoked_time"`
+}
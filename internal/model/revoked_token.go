@@ -0,0 +1,17 @@
+package model
+
+import "github.com/google/uuid"
+
+// RevokedToken is an access token that was revoked before its expiry, keyed by its jti.
+type RevokedToken struct {
+	JTI         string `gorm:"column:jti;primaryKey;type:varchar"`
+	ExpiresTime int64  `gorm:"column:expires_time;index;not null"`
+	CreatedTime int64  `gorm:"column:created_time;autoCreateTime:milli"`
+}
+
+// UserRevocation invalidates every access token of a user issued before RevokedTime.
+type UserRevocation struct {
+	UserID      uuid.UUID `gorm:"column:user_id;primaryKey;type:uuid"`
+	RevokedTime int64     `gorm:"column:revoked_time;not null"`
+	ExpiresTime int64     `gorm:"column:expires_time;index;not null"`
+}
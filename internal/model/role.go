@@ -0,0 +1,29 @@
+package model
+
+import "github.com/geekswamp/zen/internal/base"
+
+const (
+	RoleAdmin   = "admin"
+	RoleSupport = "support"
+)
+
+const (
+	PermissionUserRead   = "user:read"
+	PermissionUserUpdate = "user:update"
+	PermissionUserDelete = "user:delete"
+	PermissionRoleAssign = "role:assign"
+	PermissionAuditRead  = "audit:read"
+)
+
+type Role struct {
+	base.Model  `gorm:"embedded"`
+	Name        string       `gorm:"column:name;type:varchar;uniqueIndex;not null"`
+	Description string       `gorm:"column:description;type:varchar"`
+	Permissions []Permission `gorm:"many2many:role_permissions;constraint:OnDelete:CASCADE"`
+}
+
+type Permission struct {
+	base.Model  `gorm:"embedded"`
+	Name        string `gorm:"column:name;type:varchar;uniqueIndex;not null"`
+	Description string `gorm:"column:description;type:varchar"`
+}
@@ -14,13 +14,21 @@ const (
 
 type User struct {
 	base.Model    `gorm:"embedded"`
-	FullName      string       `gorm:"column:full_name;type:varchar;not null"`
-	Email         string       `gorm:"column:email;type:varchar;uniqueIndex;not null"`
-	Phone         string       `gorm:"column:phone;type:varchar;uniqueIndex"`
-	Active        bool         `gorm:"column:active;type:boolean;default:false;not null"`
-	Gender        Gender       `gorm:"column:gender;type:smallint;not null"`
-	ActivatedTime int64        `gorm:"column:activated_time"`
-	PassHash      UserPassHash `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	FullName      string         `gorm:"column:full_name;type:varchar;not null"`
+	Email         string         `gorm:"column:email;type:varchar;uniqueIndex:idx_users_email_alive,where:deleted_time IS NULL;not null"`
+	Phone         string         `gorm:"column:phone;type:varchar;uniqueIndex:idx_users_phone_alive,where:deleted_time IS NULL"`
+	Active        bool           `gorm:"column:active;type:boolean;default:false;not null"`
+	Gender        Gender         `gorm:"column:gender;type:smallint;not null"`
+	ActivatedTime int64          `gorm:"column:activated_time"`
+	PassHash      UserPassHash   `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	RefreshTokens []RefreshToken `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Roles         []Role         `gorm:"many2many:user_roles;constraint:OnDelete:CASCADE"`
+
+	VerificationTokens []VerificationToken `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+func (User) AuditType() string {
+	return "user"
 }
 
 type UserPassHash struct {
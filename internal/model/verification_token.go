@@ -0,0 +1,24 @@
+package model
+
+import (
+	"github.com/geekswamp/zen/internal/base"
+	"github.com/google/uuid"
+)
+
+type TokenPurpose string
+
+const (
+	EmailVerification TokenPurpose = "email_verification"
+	PasswordReset     TokenPurpose = "password_reset"
+)
+
+// VerificationToken is a single-use token sent to the user out of band, e.g. by email.
+// Only its hash is stored and a user holds at most one token per purpose.
+type VerificationToken struct {
+	base.Model  `gorm:"embedded"`
+	UserID      uuid.UUID    `gorm:"column:user_id;type:uuid;index;not null"`
+	Purpose     TokenPurpose `gorm:"column:purpose;type:varchar;not null"`
+	TokenHash   string       `gorm:"column:token_hash;type:varchar;uniqueIndex;not null"`
+	ExpiresTime int64        `gorm:"column:expires_time;not null"`
+	UsedTime    *int64       `gorm:"column:used_time"`
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/geekswamp/zen/internal/base"
+	"github.com/geekswamp/zen/internal/core"
+	"github.com/geekswamp/zen/internal/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AuditRepository interface {
+	Record(ctx context.Context, entity model.Auditable, entityID uuid.UUID, action model.AuditAction, changes model.AuditChanges) error
+	List(ctx context.Context, filter AuditFilter, offset, limit int64) ([]model.AuditEvent, int64, error)
+}
+
+// AuditFilter narrows down the audit events returned by AuditRepository.List.
+// Events are ordered by creation time, oldest first unless Descending is set.
+type AuditFilter struct {
+	EntityType  string
+	EntityID    string
+	ActorID     *uuid.UUID
+	Action      model.AuditAction
+	CreatedFrom int64
+	CreatedTo   int64
+	Descending  bool
+}
+
+type AuditQueryBuilder struct{ repo base.Repository }
+
+func NewAuditRepo(repo base.Repository) AuditRepository {
+	return AuditQueryBuilder{repo: repo}
+}
+
+// Record writes an audit event for a change that is not recorded from the rows it touches,
+// such as an assignment of roles. Like the recorded ones, the event carries the user
+// session and request id found in the context and is part of its transaction.
+func (q AuditQueryBuilder) Record(ctx context.Context, entity model.Auditable, entityID uuid.UUID, action model.AuditAction, changes model.AuditChanges) error {
+	if changes == nil {
+		changes = model.AuditChanges{}
+	}
+
+	event := model.AuditEvent{
+		RequestID:  core.RequestIDFrom(ctx),
+		EntityType: entity.AuditType(),
+		EntityID:   entityID.String(),
+		Action:     action,
+		Changes:    changes,
+	}
+
+	if session := core.UserSessionFrom(ctx); session != nil {
+		event.ActorID = &session.ID
+	}
+
+	return q.repo.DB(ctx).Create(&event).Error
+}
+
+// List returns a page of the audit events matching the filter and the total number of matching events.
+func (q AuditQueryBuilder) List(ctx context.Context, filter AuditFilter, offset, limit int64) ([]model.AuditEvent, int64, error) {
+	var total int64
+	if err := q.repo.DB(ctx).Model(&model.AuditEvent{}).Scopes(filterAudit(filter)).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	events := []model.AuditEvent{}
+	err := q.repo.DB(ctx).Scopes(filterAudit(filter)).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: "created_time"}, Desc: filter.Descending}).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: "id"}, Desc: filter.Descending}).
+		Offset(int(offset)).
+		Limit(int(limit)).
+		Find(&events).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return events, total, nil
+}
+
+func filterAudit(filter AuditFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.EntityType != "" {
+			db = db.Where("entity_type = ?", filter.EntityType)
+		}
+
+		if filter.EntityID != "" {
+			db = db.Where("entity_id = ?", filter.EntityID)
+		}
+
+		if filter.ActorID != nil {
+			db = db.Where("actor_id = ?", *filter.ActorID)
+		}
+
+		if filter.Action != "" {
+			db = db.Where("action = ?", filter.Action)
+		}
+
+		if filter.CreatedFrom > 0 {
+			db = db.Where("created_time >= ?", filter.CreatedFrom)
+		}
+
+		if filter.CreatedTo > 0 {
+			db = db.Where("created_time <= ?", filter.CreatedTo)
+		}
+
+		return db
+	}
+}
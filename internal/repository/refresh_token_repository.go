@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+
+	"time"
+
+	"github.com/geekswamp/zen/internal/base"
+	"github.com/geekswamp/zen/internal/errors"
+	"github.com/geekswamp/zen/internal/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token model.RefreshToken) error
+	FindByHash(ctx context.Context, hash string) (*model.RefreshToken, error)
+	Rotate(ctx context.Context, id uuid.UUID, next model.RefreshToken) error
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+	RevokeByUser(ctx context.Context, userID uuid.UUID) error
+}
+
+type RefreshTokenQueryBuilder struct{ repo base.Repository }
+
+func NewRefreshTokenRepo(repo base.Repository) RefreshTokenRepository {
+	return RefreshTokenQueryBuilder{repo: repo}
+}
+
+func (q RefreshTokenQueryBuilder) Create(ctx context.Context, token model.RefreshToken) error {
+	return q.repo.DB(ctx).Create(&token).Error
+}
+
+func (q RefreshTokenQueryBuilder) FindByHash(ctx context.Context, hash string) (*model.RefreshToken, error) {
+	token := model.RefreshToken{}
+	if err := q.repo.DB(ctx).Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// Rotate marks the token as used and stores its successor in a single transaction.
+// It returns errors.ErrRefreshTokenReused when the token was already used or revoked
+// concurrently, in which case nothing is written.
+func (q RefreshTokenQueryBuilder) Rotate(ctx context.Context, id uuid.UUID, next model.RefreshToken) error {
+	return q.repo.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		qr := tx.Model(&model.RefreshToken{}).
+			Where("id = ? AND used_time IS NULL AND revoked_time IS NULL", id).
+			Update("used_time", time.Now().Local().UnixMilli())
+		if qr.Error != nil {
+			return qr.Error
+		}
+
+		if qr.RowsAffected == 0 {
+			return errors.ErrRefreshTokenReused
+		}
+
+		return tx.Create(&next).Error
+	})
+}
+
+func (q RefreshTokenQueryBuilder) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	return q.repo.DB(ctx).Model(&model.RefreshToken{}).
+		Where("family_id = ? AND revoked_time IS NULL", familyID).
+		Update("revoked_time", time.Now().Local().UnixMilli()).Error
+}
+
+func (q RefreshTokenQueryBuilder) RevokeByUser(ctx context.Context, userID uuid.UUID) error {
+	return q.repo.DB(ctx).Model(&model.RefreshToken{}).
+		Where("user_id = ? AND revoked_time IS NULL", userID).
+		Update("revoked_time", time.Now().Local().UnixMilli()).Error
+}
@@ -0,0 +1,70 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/geekswamp/zen/internal/base"
+	"github.com/geekswamp/zen/internal/errors"
+	"github.com/geekswamp/zen/internal/model"
+	"github.com/geekswamp/zen/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func newMockRepo(t *testing.T) (base.Repository, sqlmock.Sqlmock) {
+	conn, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{SkipDefaultTransaction: true})
+	require.NoError(t, err)
+
+	return base.NewRepo(db), sqlMock
+}
+
+func TestRotate(t *testing.T) {
+	id := uuid.New()
+	next := model.RefreshToken{UserID: uuid.New(), FamilyID: uuid.New(), TokenHash: "next"}
+
+	testCases := []struct {
+		name    string
+		expect  func(m sqlmock.Sqlmock)
+		wantErr error
+	}{
+		{
+			name: "Rotated",
+			expect: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectExec(`UPDATE "refresh_tokens" SET "used_time"=.* WHERE \(id = .* AND used_time IS NULL AND revoked_time IS NULL\)`).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.ExpectQuery(`INSERT INTO "refresh_tokens"`).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "version"}).AddRow(uuid.New(), 1))
+				m.ExpectCommit()
+			},
+		},
+		{
+			name: "Used Or Revoked Token",
+			expect: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectExec(`UPDATE "refresh_tokens" SET "used_time"=`).WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectRollback()
+			},
+			wantErr: errors.ErrRefreshTokenReused,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, sqlMock := newMockRepo(t)
+			tc.expect(sqlMock)
+
+			err := repository.NewRefreshTokenRepo(repo).Rotate(t.Context(), id, next)
+			assert.Equal(t, tc.wantErr, err)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
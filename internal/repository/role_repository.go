@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/geekswamp/zen/internal/base"
+	"github.com/geekswamp/zen/internal/model"
+	"github.com/google/uuid"
+)
+
+type RoleRepository interface {
+	FindByUser(ctx context.Context, userID uuid.UUID) ([]model.Role, error)
+	FindByNames(ctx context.Context, names []string) ([]model.Role, error)
+	AssignToUser(ctx context.Context, userID uuid.UUID, roles []model.Role) error
+}
+
+type RoleQueryBuilder struct{ repo base.Repository }
+
+func NewRoleRepo(repo base.Repository) RoleRepository {
+	return RoleQueryBuilder{repo: repo}
+}
+
+func (q RoleQueryBuilder) FindByUser(ctx context.Context, userID uuid.UUID) ([]model.Role, error) {
+	roles := []model.Role{}
+	err := q.repo.DB(ctx).Preload("Permissions").
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userID).
+		Find(&roles).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+func (q RoleQueryBuilder) FindByNames(ctx context.Context, names []string) ([]model.Role, error) {
+	roles := []model.Role{}
+	if err := q.repo.DB(ctx).Where("name IN ?", names).Find(&roles).Error; err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+// AssignToUser replaces the roles of the user with the given ones.
+func (q RoleQueryBuilder) AssignToUser(ctx context.Context, userID uuid.UUID, roles []model.Role) error {
+	user := model.User{Model: base.Model{ID: userID}}
+	return q.repo.DB(ctx).Model(&user).Association("Roles").Replace(roles)
+}
@@ -1,28 +1,65 @@
 package repository
 
 import (
+	"context"
+	"strings"
+
 	"github.com/geekswamp/zen/internal/base"
+	"github.com/geekswamp/zen/internal/http"
 	"github.com/geekswamp/zen/internal/model"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository interface {
-	Create(user model.User, passHash string) error
-	FindByID(id uuid.UUID) (*model.User, error)
-	IsExist(user *model.User) (bool, error)
-	Update(id uuid.UUID, userMap base.UpdateMap) error
-	Delete(id uuid.UUID) error
+	Create(ctx context.Context, user model.User, passHash string) error
+	FindByID(ctx context.Context, id uuid.UUID) (*model.User, error)
+	FindByEmail(ctx context.Context, email string) (*model.User, error)
+	FindPassHash(ctx context.Context, id uuid.UUID) (string, error)
+	List(ctx context.Context, filter UserFilter, offset, limit int64) ([]model.User, int64, error)
+	ListByCursor(ctx context.Context, filter UserFilter, cursor *http.Cursor, limit int64) (*base.KeysetResult[model.User], error)
+	IsExist(ctx context.Context, user *model.User) (bool, error)
+	Update(ctx context.Context, id uuid.UUID, userMap base.UpdateMap) error
+	UpdateVersion(ctx context.Context, id uuid.UUID, version int64, userMap base.UpdateMap) error
+	UpdatePassHash(ctx context.Context, id uuid.UUID, passHash string) error
+	SoftDelete(ctx context.Context, id uuid.UUID, version int64) error
+	Restore(ctx context.Context, id uuid.UUID, version int64) error
+	HardDelete(ctx context.Context, id uuid.UUID) error
 }
 
-type UserQueryBuilder struct{ repo base.Repository }
+// UserFilter narrows down and orders the users returned by UserRepository.List.
+// SortBy must be one of the keys of userSortColumns, otherwise users are sorted by
+// creation time. ListByCursor ignores the sort fields and returns the newest users first.
+type UserFilter struct {
+	Search         string
+	Active         *bool
+	Gender         *model.Gender
+	CreatedFrom    int64
+	CreatedTo      int64
+	IncludeDeleted bool
+	SortBy         string
+	Descending     bool
+}
+
+var userSortColumns = map[string]string{
+	"full_name":      "full_name",
+	"email":          "email",
+	"created_time":   "created_time",
+	"activated_time": "activated_time",
+}
+
+type UserQueryBuilder struct {
+	base.CRUD[model.User]
+	repo base.Repository
+}
 
 func NewUserRepo(repo base.Repository) UserRepository {
-	return UserQueryBuilder{repo: repo}
+	return UserQueryBuilder{CRUD: base.NewCRUD[model.User](repo), repo: repo}
 }
 
-func (q UserQueryBuilder) Create(user model.User, passHash string) error {
-	err := q.repo.DB().Transaction(func(tx *gorm.DB) error {
+func (q UserQueryBuilder) Create(ctx context.Context, user model.User, passHash string) error {
+	err := q.repo.DB(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Create(&user).Error; err != nil {
 			return err
 		}
@@ -40,40 +77,97 @@ func (q UserQueryBuilder) Create(user model.User, passHash string) error {
 	return err
 }
 
-func (q UserQueryBuilder) FindByID(id uuid.UUID) (*model.User, error) {
+func (q UserQueryBuilder) FindByEmail(ctx context.Context, email string) (*model.User, error) {
 	user := model.User{}
-	if err := q.repo.DB().First(&user, id).Error; err != nil {
+	if err := q.repo.DB(ctx).Preload("PassHash").Where("email = ?", email).First(&user).Error; err != nil {
 		return nil, err
 	}
 
 	return &user, nil
 }
 
-func (q UserQueryBuilder) IsExist(user *model.User) (bool, error) {
-	err := q.repo.DB().Where("email = ?", user.Email).Or("phone = ?", user.Phone).First(&model.User{}).Error
-	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return false, nil
-		}
+func (q UserQueryBuilder) FindPassHash(ctx context.Context, id uuid.UUID) (string, error) {
+	passHash := model.UserPassHash{}
+	if err := q.repo.DB(ctx).Where("user_id = ?", id).First(&passHash).Error; err != nil {
+		return "", err
+	}
 
-		return false, err
+	return passHash.PassHash, nil
+}
+
+// List returns a page of the users matching the filter and the total number of matching users.
+func (q UserQueryBuilder) List(ctx context.Context, filter UserFilter, offset, limit int64) ([]model.User, int64, error) {
+	var total int64
+	if err := q.repo.DB(ctx).Model(&model.User{}).Scopes(filterUsers(filter)).Count(&total).Error; err != nil {
+		return nil, 0, err
 	}
 
-	return true, nil
+	column, ok := userSortColumns[filter.SortBy]
+	if !ok {
+		column = "created_time"
+	}
+
+	users := []model.User{}
+	err := q.repo.DB(ctx).Scopes(filterUsers(filter)).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: column}, Desc: filter.Descending}).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: "id"}, Desc: filter.Descending}).
+		Offset(int(offset)).
+		Limit(int(limit)).
+		Find(&users).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
 }
 
-func (q UserQueryBuilder) Update(id uuid.UUID, userMap base.UpdateMap) error {
-	qr := q.repo.DB().Model(&model.User{}).Where("id = ?", id).Updates(userMap)
+// ListByCursor returns the page of users matching the filter on the side of the cursor.
+func (q UserQueryBuilder) ListByCursor(ctx context.Context, filter UserFilter, cursor *http.Cursor, limit int64) (*base.KeysetResult[model.User], error) {
+	return base.FindKeyset[model.User](q.repo.DB(ctx).Scopes(filterUsers(filter)), cursor, limit)
+}
 
-	if qr.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
+func filterUsers(filter UserFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.Search != "" {
+			pattern := "%" + escapeLike(filter.Search) + "%"
+			db = db.Where("full_name ILIKE ? OR email ILIKE ? OR phone ILIKE ?", pattern, pattern, pattern)
+		}
+
+		if filter.Active != nil {
+			db = db.Where("active = ?", *filter.Active)
+		}
+
+		if filter.Gender != nil {
+			db = db.Where("gender = ?", *filter.Gender)
+		}
+
+		if filter.CreatedFrom > 0 {
+			db = db.Where("created_time >= ?", filter.CreatedFrom)
+		}
+
+		if filter.CreatedTo > 0 {
+			db = db.Where("created_time <= ?", filter.CreatedTo)
+		}
+
+		if filter.IncludeDeleted {
+			db = db.Scopes(base.WithDeleted)
+		}
+
+		return db
 	}
+}
 
-	return qr.Error
+// escapeLike escapes the wildcard characters of a LIKE pattern.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func (q UserQueryBuilder) IsExist(ctx context.Context, user *model.User) (bool, error) {
+	return q.Exists(ctx, base.Where("email = ? OR phone = ?", user.Email, user.Phone))
 }
 
-func (q UserQueryBuilder) Delete(id uuid.UUID) error {
-	qr := q.repo.DB().Unscoped().Model(&model.User{}).Where("id = ?", id).Delete(&model.User{})
+func (q UserQueryBuilder) UpdatePassHash(ctx context.Context, id uuid.UUID, passHash string) error {
+	qr := q.repo.DB(ctx).Model(&model.UserPassHash{}).Where("user_id = ?", id).Update("pass_hash", passHash)
 
 	if qr.RowsAffected == 0 {
 		return gorm.ErrRecordNotFound
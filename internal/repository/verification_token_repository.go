@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+
+	"time"
+
+	"github.com/geekswamp/zen/internal/base"
+	"github.com/geekswamp/zen/internal/errors"
+	"github.com/geekswamp/zen/internal/model"
+	"gorm.io/gorm"
+)
+
+type VerificationTokenRepository interface {
+	Replace(ctx context.Context, token model.VerificationToken) error
+	Consume(ctx context.Context, hash string, purpose model.TokenPurpose) (*model.VerificationToken, error)
+}
+
+type VerificationTokenQueryBuilder struct{ repo base.Repository }
+
+func NewVerificationTokenRepo(repo base.Repository) VerificationTokenRepository {
+	return VerificationTokenQueryBuilder{repo: repo}
+}
+
+// Replace stores the token and drops any other token the user holds for the same purpose.
+func (q VerificationTokenQueryBuilder) Replace(ctx context.Context, token model.VerificationToken) error {
+	return q.repo.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("user_id = ? AND purpose = ?", token.UserID, token.Purpose).
+			Delete(&model.VerificationToken{}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(&token).Error
+	})
+}
+
+// Consume marks the token as used and returns it. It returns errors.ErrInvalidVerificationToken
+// when the token does not exist, has expired or was already used.
+func (q VerificationTokenQueryBuilder) Consume(ctx context.Context, hash string, purpose model.TokenPurpose) (*model.VerificationToken, error) {
+	token := model.VerificationToken{}
+	err := q.repo.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("token_hash = ? AND purpose = ?", hash, purpose).First(&token).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.ErrInvalidVerificationToken
+			}
+
+			return err
+		}
+
+		now := time.Now().Local().UnixMilli()
+		qr := tx.Model(&model.VerificationToken{}).
+			Where("id = ? AND used_time IS NULL AND expires_time > ?", token.ID, now).
+			Update("used_time", now)
+		if qr.Error != nil {
+			return qr.Error
+		}
+
+		if qr.RowsAffected == 0 {
+			return errors.ErrInvalidVerificationToken
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
@@ -2,19 +2,50 @@ package router
 
 import (
 	"github.com/geekswamp/zen/internal/di"
+	"github.com/geekswamp/zen/internal/model"
+	"github.com/geekswamp/zen/pkg/http/middleware"
 	"github.com/gin-gonic/gin"
 )
 
 func RegisterRouter(engine *gin.Engine) {
+	wellKnownGroup := engine.Group("/.well-known")
+
+	wellKnownHandler := di.InitWellKnownHandler()
+	wellKnownGroup.GET("/jwks.json", wellKnownHandler.JWKS)
+
 	apiV1 := engine.Group("/api/v1")
+	authMiddleware := middleware.Auth(di.InitJWTVerifier(), di.InitRevocationStore())
+
+	authGroup := apiV1.Group("/auth")
+
+	authHandler := di.InitAuthHandler()
+	authGroup.POST("/login", authHandler.Login)
+	authGroup.POST("/refresh", authHandler.Refresh)
+	authGroup.POST("/logout", authMiddleware, authHandler.Logout)
+	authGroup.POST("/password/forgot", authHandler.ForgotPassword)
+	authGroup.POST("/password/reset", authHandler.ResetPassword)
 
 	userGroup := apiV1.Group("/user")
 
 	userHandler := di.InitUserHandler()
+	userGroup.GET("", authMiddleware, middleware.RequirePermission(model.PermissionUserRead), userHandler.List)
+	userGroup.GET("/cursor", authMiddleware, middleware.RequirePermission(model.PermissionUserRead), userHandler.ListByCursor)
 	userGroup.POST("/register", userHandler.Register)
-	userGroup.GET("/detail/:id", userHandler.GetDetail)
-	userGroup.DELETE("/delete/:id", userHandler.HardDelete)
-	userGroup.PATCH("/mark-delete/:id", userHandler.SoftDelete)
-	userGroup.PATCH("/set-active/:id", userHandler.SetToActive)
-	userGroup.PATCH("/set-inactive/:id", userHandler.SetToInactive)
+	userGroup.GET("/verify", userHandler.Verify)
+	userGroup.GET("/current", authMiddleware, userHandler.GetCurrent)
+	userGroup.PATCH("/update", authMiddleware, userHandler.Update)
+	userGroup.PUT("/password", authMiddleware, userHandler.ChangePassword)
+	userGroup.GET("/detail/:id", authMiddleware, middleware.RequirePermission(model.PermissionUserRead), userHandler.GetDetail)
+	userGroup.DELETE("/delete/:id", authMiddleware, middleware.RequirePermission(model.PermissionUserDelete), userHandler.HardDelete)
+	userGroup.PATCH("/mark-delete/:id", authMiddleware, middleware.RequirePermission(model.PermissionUserDelete), userHandler.SoftDelete)
+	userGroup.PATCH("/restore/:id", authMiddleware, middleware.RequirePermission(model.PermissionUserDelete), userHandler.Restore)
+	userGroup.PATCH("/set-active/:id", authMiddleware, middleware.RequirePermission(model.PermissionUserUpdate), userHandler.SetToActive)
+	userGroup.PATCH("/set-inactive/:id", authMiddleware, middleware.RequirePermission(model.PermissionUserUpdate), userHandler.SetToInactive)
+	userGroup.PATCH("/revoke-tokens/:id", authMiddleware, middleware.RequirePermission(model.PermissionUserUpdate), userHandler.RevokeTokens)
+	userGroup.PUT("/roles/:id", authMiddleware, middleware.RequirePermission(model.PermissionRoleAssign), userHandler.SetRoles)
+
+	auditGroup := apiV1.Group("/audit")
+
+	auditHandler := di.InitAuditHandler()
+	auditGroup.GET("", authMiddleware, middleware.RequirePermission(model.PermissionAuditRead), auditHandler.List)
 }
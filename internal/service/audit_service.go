@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+
+	"github.com/geekswamp/zen/internal/model"
+	"github.com/geekswamp/zen/internal/repository"
+)
+
+type AuditService interface {
+	List(ctx context.Context, filter repository.AuditFilter, offset, limit int64) ([]model.AuditEvent, int64, error)
+}
+
+type AuditServiceRepo struct {
+	repo repository.AuditRepository
+}
+
+func NewAuditService(repo repository.AuditRepository) AuditService {
+	return AuditServiceRepo{repo: repo}
+}
+
+func (s AuditServiceRepo) List(ctx context.Context, filter repository.AuditFilter, offset, limit int64) ([]model.AuditEvent, int64, error) {
+	return s.repo.List(ctx, filter, offset, limit)
+}
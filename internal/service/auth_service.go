@@ -0,0 +1,357 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/geekswamp/zen/configs"
+	"github.com/geekswamp/zen/internal/base"
+	"github.com/geekswamp/zen/internal/core"
+	"github.com/geekswamp/zen/internal/crypto/key"
+	"github.com/geekswamp/zen/internal/crypto/password"
+	"github.com/geekswamp/zen/internal/crypto/token"
+	"github.com/geekswamp/zen/internal/errors"
+	"github.com/geekswamp/zen/internal/logger"
+	"github.com/geekswamp/zen/internal/mail"
+	"github.com/geekswamp/zen/internal/model"
+	"github.com/geekswamp/zen/internal/repository"
+	"github.com/geekswamp/zen/internal/storage/revocation"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var log = logger.New()
+
+// dummyPassHash is a PHC hash of no account, with the current settings, that Login verifies
+// for unknown emails so that they take as long as wrong passwords.
+var dummyPassHash = sync.OnceValues(func() (string, error) {
+	pc := password.NewFromConfig(configs.Get())
+	return pc.Generate([]byte(uuid.NewString()))
+})
+
+type AuthToken struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    int64
+}
+
+type AuthService interface {
+	Login(ctx context.Context, email, passwordStr string) (*AuthToken, error)
+	Refresh(ctx context.Context, refreshToken string) (*AuthToken, error)
+	Logout(ctx context.Context, session core.UserSession, refreshToken string) error
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, resetToken, newPassword string) error
+}
+
+type AuthServiceRepo struct {
+	tx         base.Transactor
+	repo       repository.UserRepository
+	tokenRepo  repository.RefreshTokenRepository
+	roleRepo   repository.RoleRepository
+	verifyRepo repository.VerificationTokenRepository
+	auditRepo  repository.AuditRepository
+	revoked    revocation.Store
+	mailer     mail.Mailer
+	keys       key.KeyRing
+}
+
+func NewAuthService(
+	tx base.Transactor,
+	repo repository.UserRepository,
+	tokenRepo repository.RefreshTokenRepository,
+	roleRepo repository.RoleRepository,
+	verifyRepo repository.VerificationTokenRepository,
+	auditRepo repository.AuditRepository,
+	revoked revocation.Store,
+	mailer mail.Mailer,
+	keys key.KeyRing,
+) AuthService {
+	return AuthServiceRepo{
+		tx:         tx,
+		repo:       repo,
+		tokenRepo:  tokenRepo,
+		roleRepo:   roleRepo,
+		verifyRepo: verifyRepo,
+		auditRepo:  auditRepo,
+		revoked:    revoked,
+		mailer:     mailer,
+		keys:       keys,
+	}
+}
+
+func (s AuthServiceRepo) Login(ctx context.Context, email, passwordStr string) (*AuthToken, error) {
+	pc := password.NewFromConfig(configs.Get())
+
+	user, err := s.repo.FindByEmail(ctx, email)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			if encoded, hashErr := dummyPassHash(); hashErr == nil {
+				_, _ = pc.Verify([]byte(passwordStr), encoded)
+			}
+
+			return nil, errors.ErrInvalidCredentials
+		}
+
+		return nil, err
+	}
+
+	result, err := pc.Verify([]byte(passwordStr), user.PassHash.PassHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if !result.Valid {
+		return nil, errors.ErrInvalidCredentials
+	}
+
+	if result.NeedsRehash {
+		s.rehash(ctx, pc, user.ID, passwordStr)
+	}
+
+	if !user.Active {
+		return nil, errors.ErrUserInactive
+	}
+
+	refreshToken, next, err := newRefreshToken(user.ID, uuid.New())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.tokenRepo.Create(ctx, *next); err != nil {
+		return nil, err
+	}
+
+	return s.issue(ctx, user.ID, next.FamilyID, refreshToken)
+}
+
+// Refresh exchanges a refresh token for a new access and refresh token pair.
+// The presented token is consumed; presenting it again revokes its whole family.
+func (s AuthServiceRepo) Refresh(ctx context.Context, refreshToken string) (*AuthToken, error) {
+	current, err := s.tokenRepo.FindByHash(ctx, token.HashOpaque(refreshToken))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrInvalidRefreshToken
+		}
+
+		return nil, err
+	}
+
+	if current.RevokedTime != nil {
+		return nil, errors.ErrInvalidRefreshToken
+	}
+
+	if current.UsedTime != nil {
+		return nil, s.revokeReused(ctx, current.FamilyID)
+	}
+
+	if current.ExpiresTime <= time.Now().Local().UnixMilli() {
+		return nil, errors.ErrInvalidRefreshToken
+	}
+
+	user, err := s.repo.FindByID(ctx, current.UserID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrInvalidRefreshToken
+		}
+
+		return nil, err
+	}
+
+	if !user.Active {
+		return nil, errors.ErrUserInactive
+	}
+
+	plain, next, err := newRefreshToken(user.ID, current.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.tokenRepo.Rotate(ctx, current.ID, *next); err != nil {
+		if err == errors.ErrRefreshTokenReused {
+			return nil, s.revokeReused(ctx, current.FamilyID)
+		}
+
+		return nil, err
+	}
+
+	return s.issue(ctx, user.ID, current.FamilyID, plain)
+}
+
+// Logout ends the session of the caller: its access token is revoked until it expires and
+// its refresh token family is revoked. The given refresh token must belong to that session,
+// otherwise errors.ErrInvalidRefreshToken is returned and nothing is revoked; unknown refresh
+// tokens are ignored. Access tokens without an expiry are revoked for one access token lifetime.
+func (s AuthServiceRepo) Logout(ctx context.Context, session core.UserSession, refreshToken string) error {
+	familyID, err := uuid.Parse(session.SessionID)
+	if err != nil {
+		return errors.ErrInvalidRefreshToken
+	}
+
+	current, err := s.tokenRepo.FindByHash(ctx, token.HashOpaque(refreshToken))
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return err
+	}
+
+	if current != nil && (current.UserID != session.ID || current.FamilyID != familyID) {
+		return errors.ErrInvalidRefreshToken
+	}
+
+	expiresAt := session.ExpiresAt
+	if expiresAt.IsZero() {
+		expiresAt = time.Now().Add(configs.Get().JWT.AccessTokenTTL)
+	}
+
+	if err := s.revoked.Revoke(ctx, session.TokenID, expiresAt); err != nil {
+		return err
+	}
+
+	return s.tokenRepo.RevokeFamily(ctx, familyID)
+}
+
+// ForgotPassword mails a password reset link to the user with the given email.
+// It reports no error for unknown users or failed deliveries, and the link is sent in
+// the background, so that callers cannot tell whether an account exists, neither from
+// the response nor from its timing.
+func (s AuthServiceRepo) ForgotPassword(ctx context.Context, email string) error {
+	user, err := s.repo.FindByEmail(ctx, email)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+
+		return err
+	}
+
+	// The request may end before the mail is sent, keep its values but not its cancellation.
+	go s.sendPasswordReset(context.WithoutCancel(ctx), user.ID, user.Email)
+
+	return nil
+}
+
+// sendPasswordReset mails a new password reset link to the user and logs a failure.
+func (s AuthServiceRepo) sendPasswordReset(ctx context.Context, userID uuid.UUID, email string) {
+	cfg := configs.Get().PasswordReset
+	err := sendTokenMail(ctx, s.verifyRepo, s.mailer, userID, email, tokenMail{
+		purpose: model.PasswordReset,
+		ttl:     cfg.TokenTTL,
+		url:     cfg.URL,
+		subject: "Reset your password",
+		intro:   "Open the link below to choose a new password. If you did not ask for it, you can ignore this email.",
+	})
+	if err != nil {
+		log.Error(errors.ErrFailedToSendPasswordReset.Error(), logger.ErrDetails(err), logger.RequestID(core.RequestIDFrom(ctx)))
+	}
+}
+
+// ResetPassword consumes a password reset token and sets the new password of its user.
+// Every session of the user is revoked and the reset is audited.
+func (s AuthServiceRepo) ResetPassword(ctx context.Context, resetToken, newPassword string) error {
+	pc := password.NewFromConfig(configs.Get())
+	hash, err := pc.Generate([]byte(newPassword))
+	if err != nil {
+		return err
+	}
+
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		vt, err := s.verifyRepo.Consume(ctx, token.HashOpaque(resetToken), model.PasswordReset)
+		if err != nil {
+			return err
+		}
+
+		if err := s.repo.UpdatePassHash(ctx, vt.UserID, hash); err != nil {
+			return err
+		}
+
+		if err := s.revoked.RevokeUser(ctx, vt.UserID, time.Now()); err != nil {
+			return err
+		}
+
+		if err := s.tokenRepo.RevokeByUser(ctx, vt.UserID); err != nil {
+			return err
+		}
+
+		return s.auditRepo.Record(ctx, model.User{}, vt.UserID, model.AuditPasswordReset, nil)
+	})
+}
+
+// issue creates an access token bound to the refresh token family, which identifies the login session.
+// The roles of the user and the permissions they grant are embedded in the token.
+func (s AuthServiceRepo) issue(ctx context.Context, userID, familyID uuid.UUID, refreshToken string) (*AuthToken, error) {
+	roles, err := s.roleRepo.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	roleNames, permissions := flattenRoles(roles)
+
+	cfg := configs.Get()
+	accessToken, err := token.New(
+		cfg.JWT.Issuer, userID.String(), cfg.JWT.Audience, cfg.JWT.AccessTokenTTL, s.keys,
+		token.WithSessionID(familyID.String()),
+		token.WithRoles(roleNames, permissions),
+	).Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthToken{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(cfg.JWT.AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+// revokeReused revokes a refresh token family after one of its tokens was presented twice.
+func (s AuthServiceRepo) revokeReused(ctx context.Context, familyID uuid.UUID) error {
+	log.Warn(errors.ErrRefreshTokenReused.Error(), logger.RequestID(core.RequestIDFrom(ctx)))
+
+	if err := s.tokenRepo.RevokeFamily(ctx, familyID); err != nil {
+		return err
+	}
+
+	return errors.ErrRefreshTokenReused
+}
+
+// rehash upgrades a stored hash to the current argon2id parameters.
+// Failures are logged only, since the user has already been authenticated.
+func (s AuthServiceRepo) rehash(ctx context.Context, pc password.Config, id uuid.UUID, passwordStr string) {
+	hash, err := pc.Generate([]byte(passwordStr))
+	if err != nil {
+		return
+	}
+
+	if err := s.repo.UpdatePassHash(ctx, id, hash); err != nil {
+		log.Error(errors.ErrFailedToRehash.Error(), logger.ErrDetails(err), logger.RequestID(core.RequestIDFrom(ctx)))
+	}
+}
+
+func newRefreshToken(userID, familyID uuid.UUID) (string, *model.RefreshToken, error) {
+	plain, hash, err := token.NewOpaque()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return plain, &model.RefreshToken{
+		UserID:      userID,
+		FamilyID:    familyID,
+		TokenHash:   hash,
+		ExpiresTime: time.Now().Local().Add(configs.Get().JWT.RefreshTokenTTL).UnixMilli(),
+	}, nil
+}
+
+// flattenRoles returns the role names and the sorted, de-duplicated union of their permissions.
+func flattenRoles(roles []model.Role) ([]string, []string) {
+	roleNames := make([]string, 0, len(roles))
+	permissions := []string{}
+	for _, role := range roles {
+		roleNames = append(roleNames, role.Name)
+		for _, p := range role.Permissions {
+			permissions = append(permissions, p.Name)
+		}
+	}
+
+	slices.Sort(permissions)
+	return roleNames, slices.Compact(permissions)
+}
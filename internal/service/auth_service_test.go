@@ -0,0 +1,307 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/geekswamp/zen/internal/base"
+	"github.com/geekswamp/zen/internal/core"
+	"github.com/geekswamp/zen/internal/crypto/token"
+	"github.com/geekswamp/zen/internal/errors"
+	"github.com/geekswamp/zen/internal/mail"
+	"github.com/geekswamp/zen/internal/model"
+	"github.com/geekswamp/zen/internal/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func newAuthService(t *testing.T, m mocks) service.AuthService {
+	return service.NewAuthService(MockTransactor{}, m.users, m.tokens, m.roles, m.verifyRepo, m.auditRepo, m.revoked, m.mailer, newKeyRing(t))
+}
+
+func TestRefresh(t *testing.T) {
+	const presented = "presented-refresh-token"
+
+	userID := uuid.New()
+	familyID := uuid.New()
+	now := time.Now().UnixMilli()
+	current := func(edit func(rt *model.RefreshToken)) *model.RefreshToken {
+		rt := &model.RefreshToken{
+			Model:       base.Model{ID: uuid.New()},
+			UserID:      userID,
+			FamilyID:    familyID,
+			TokenHash:   token.HashOpaque(presented),
+			ExpiresTime: now + time.Hour.Milliseconds(),
+		}
+		if edit != nil {
+			edit(rt)
+		}
+		return rt
+	}
+	nextOfFamily := mock.MatchedBy(func(next model.RefreshToken) bool {
+		return next.UserID == userID && next.FamilyID == familyID && next.TokenHash != token.HashOpaque(presented)
+	})
+
+	testCases := []struct {
+		name    string
+		setup   func(m mocks)
+		wantErr error
+	}{
+		{
+			name: "Rotated",
+			setup: func(m mocks) {
+				rt := current(nil)
+				m.tokens.On("FindByHash", rt.TokenHash).Return(rt, nil)
+				m.users.On("FindByID", userID).Return(&model.User{Model: base.Model{ID: userID}, Active: true}, nil)
+				m.tokens.On("Rotate", rt.ID, nextOfFamily).Return(nil)
+				m.roles.On("FindByUser", userID).Return([]model.Role{}, nil)
+			},
+		},
+		{
+			name: "Unknown Token",
+			setup: func(m mocks) {
+				m.tokens.On("FindByHash", token.HashOpaque(presented)).Return(nil, gorm.ErrRecordNotFound)
+			},
+			wantErr: errors.ErrInvalidRefreshToken,
+		},
+		{
+			name: "Consumed Token Revokes Family",
+			setup: func(m mocks) {
+				m.tokens.On("FindByHash", token.HashOpaque(presented)).Return(current(func(rt *model.RefreshToken) { rt.UsedTime = &now }), nil)
+				m.tokens.On("RevokeFamily", familyID).Return(nil)
+			},
+			wantErr: errors.ErrRefreshTokenReused,
+		},
+		{
+			name: "Concurrently Consumed Token Revokes Family",
+			setup: func(m mocks) {
+				rt := current(nil)
+				m.tokens.On("FindByHash", rt.TokenHash).Return(rt, nil)
+				m.users.On("FindByID", userID).Return(&model.User{Model: base.Model{ID: userID}, Active: true}, nil)
+				m.tokens.On("Rotate", rt.ID, nextOfFamily).Return(errors.ErrRefreshTokenReused)
+				m.tokens.On("RevokeFamily", familyID).Return(nil)
+			},
+			wantErr: errors.ErrRefreshTokenReused,
+		},
+		{
+			name: "Expired Token",
+			setup: func(m mocks) {
+				m.tokens.On("FindByHash", token.HashOpaque(presented)).Return(current(func(rt *model.RefreshToken) { rt.ExpiresTime = now - 1 }), nil)
+			},
+			wantErr: errors.ErrInvalidRefreshToken,
+		},
+		{
+			name: "Revoked Token",
+			setup: func(m mocks) {
+				m.tokens.On("FindByHash", token.HashOpaque(presented)).Return(current(func(rt *model.RefreshToken) { rt.RevokedTime = &now }), nil)
+			},
+			wantErr: errors.ErrInvalidRefreshToken,
+		},
+		{
+			name: "Inactive User",
+			setup: func(m mocks) {
+				m.tokens.On("FindByHash", token.HashOpaque(presented)).Return(current(nil), nil)
+				m.users.On("FindByID", userID).Return(&model.User{Model: base.Model{ID: userID}}, nil)
+			},
+			wantErr: errors.ErrUserInactive,
+		},
+		{
+			name: "Deleted User",
+			setup: func(m mocks) {
+				m.tokens.On("FindByHash", token.HashOpaque(presented)).Return(current(nil), nil)
+				m.users.On("FindByID", userID).Return(nil, gorm.ErrRecordNotFound)
+			},
+			wantErr: errors.ErrInvalidRefreshToken,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newMocks()
+			tc.setup(m)
+
+			authToken, err := newAuthService(t, m).Refresh(t.Context(), presented)
+			if tc.wantErr != nil {
+				assert.Equal(t, tc.wantErr, err)
+				assert.Nil(t, authToken)
+			} else {
+				require.NoError(t, err)
+				assert.NotEmpty(t, authToken.AccessToken)
+				assert.NotEmpty(t, authToken.RefreshToken)
+				assert.NotEqual(t, presented, authToken.RefreshToken)
+			}
+
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestLogout(t *testing.T) {
+	const presented = "presented-refresh-token"
+
+	userID := uuid.New()
+	familyID := uuid.New()
+	expiresAt := time.Now().Add(time.Minute)
+	session := core.UserSession{ID: userID, SessionID: familyID.String(), TokenID: "jti", ExpiresAt: expiresAt}
+	refreshToken := func(userID, familyID uuid.UUID) *model.RefreshToken {
+		return &model.RefreshToken{UserID: userID, FamilyID: familyID, TokenHash: token.HashOpaque(presented)}
+	}
+
+	testCases := []struct {
+		name    string
+		setup   func(m mocks)
+		wantErr error
+	}{
+		{
+			name: "Current Session",
+			setup: func(m mocks) {
+				m.tokens.On("FindByHash", token.HashOpaque(presented)).Return(refreshToken(userID, familyID), nil)
+				m.revoked.On("Revoke", "jti", expiresAt).Return(nil)
+				m.tokens.On("RevokeFamily", familyID).Return(nil)
+			},
+		},
+		{
+			name: "Unknown Refresh Token Still Ends Session",
+			setup: func(m mocks) {
+				m.tokens.On("FindByHash", token.HashOpaque(presented)).Return(nil, gorm.ErrRecordNotFound)
+				m.revoked.On("Revoke", "jti", expiresAt).Return(nil)
+				m.tokens.On("RevokeFamily", familyID).Return(nil)
+			},
+		},
+		{
+			name: "Refresh Token Of Another Session",
+			setup: func(m mocks) {
+				m.tokens.On("FindByHash", token.HashOpaque(presented)).Return(refreshToken(userID, uuid.New()), nil)
+			},
+			wantErr: errors.ErrInvalidRefreshToken,
+		},
+		{
+			name: "Refresh Token Of Another User",
+			setup: func(m mocks) {
+				m.tokens.On("FindByHash", token.HashOpaque(presented)).Return(refreshToken(uuid.New(), familyID), nil)
+			},
+			wantErr: errors.ErrInvalidRefreshToken,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newMocks()
+			tc.setup(m)
+
+			err := newAuthService(t, m).Logout(t.Context(), session, presented)
+			assert.Equal(t, tc.wantErr, err)
+			if tc.wantErr != nil {
+				m.tokens.AssertNotCalled(t, "RevokeFamily", mock.Anything)
+				m.revoked.AssertNotCalled(t, "Revoke", mock.Anything, mock.Anything)
+			}
+
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestForgotPassword(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("Unknown Email", func(t *testing.T) {
+		m := newMocks()
+		m.users.On("FindByEmail", "nobody@example.com").Return(nil, gorm.ErrRecordNotFound)
+
+		err := newAuthService(t, m).ForgotPassword(t.Context(), "nobody@example.com")
+		assert.NoError(t, err)
+
+		m.verifyRepo.AssertNotCalled(t, "Replace", mock.Anything)
+		m.mailer.AssertNotCalled(t, "Send", mock.Anything)
+		m.assertExpectations(t)
+	})
+
+	t.Run("Known Email", func(t *testing.T) {
+		sent := make(chan mail.Message, 1)
+
+		m := newMocks()
+		m.users.On("FindByEmail", "user@example.com").Return(&model.User{Model: base.Model{ID: userID}, Email: "user@example.com"}, nil)
+		m.verifyRepo.On("Replace", mock.MatchedBy(func(vt model.VerificationToken) bool {
+			return vt.UserID == userID && vt.Purpose == model.PasswordReset
+		})).Return(nil)
+		m.mailer.On("Send", mock.Anything).Run(func(args mock.Arguments) { sent <- args.Get(0).(mail.Message) }).Return(nil)
+
+		err := newAuthService(t, m).ForgotPassword(t.Context(), "user@example.com")
+		assert.NoError(t, err)
+
+		select {
+		case msg := <-sent:
+			assert.Equal(t, "user@example.com", msg.To)
+		case <-time.After(time.Second):
+			t.Fatal("password reset mail was not sent")
+		}
+
+		m.assertExpectations(t)
+	})
+}
+
+func TestResetPassword(t *testing.T) {
+	const resetToken = "password-reset-token"
+
+	userID := uuid.New()
+	consumed := &model.VerificationToken{UserID: userID, Purpose: model.PasswordReset, TokenHash: token.HashOpaque(resetToken)}
+
+	testCases := []struct {
+		name    string
+		setup   func(m mocks)
+		wantErr error
+	}{
+		{
+			name: "Reset Revokes Every Session",
+			setup: func(m mocks) {
+				m.verifyRepo.On("Consume", token.HashOpaque(resetToken), model.PasswordReset).Return(consumed, nil)
+				m.users.On("UpdatePassHash", userID, mock.Anything).Return(nil)
+				m.revoked.On("RevokeUser", userID, mock.Anything).Return(nil)
+				m.tokens.On("RevokeByUser", userID).Return(nil)
+				m.auditRepo.On("Record", "user", userID, model.AuditPasswordReset, model.AuditChanges(nil)).Return(nil)
+			},
+		},
+		{
+			name: "Used Or Expired Token",
+			setup: func(m mocks) {
+				m.verifyRepo.On("Consume", token.HashOpaque(resetToken), model.PasswordReset).Return(nil, errors.ErrInvalidVerificationToken)
+			},
+			wantErr: errors.ErrInvalidVerificationToken,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newMocks()
+			tc.setup(m)
+
+			err := newAuthService(t, m).ResetPassword(t.Context(), resetToken, "n3w-Passw0rd!")
+			assert.Equal(t, tc.wantErr, err)
+			if tc.wantErr != nil {
+				m.users.AssertNotCalled(t, "UpdatePassHash", mock.Anything, mock.Anything)
+			}
+
+			m.assertExpectations(t)
+		})
+	}
+
+	t.Run("Token Used Twice", func(t *testing.T) {
+		m := newMocks()
+		m.verifyRepo.On("Consume", token.HashOpaque(resetToken), model.PasswordReset).Return(consumed, nil).Once()
+		m.verifyRepo.On("Consume", token.HashOpaque(resetToken), model.PasswordReset).Return(nil, errors.ErrInvalidVerificationToken).Once()
+		m.users.On("UpdatePassHash", userID, mock.Anything).Return(nil).Once()
+		m.revoked.On("RevokeUser", userID, mock.Anything).Return(nil).Once()
+		m.tokens.On("RevokeByUser", userID).Return(nil).Once()
+		m.auditRepo.On("Record", "user", userID, model.AuditPasswordReset, model.AuditChanges(nil)).Return(nil).Once()
+
+		svc := newAuthService(t, m)
+		require.NoError(t, svc.ResetPassword(t.Context(), resetToken, "n3w-Passw0rd!"))
+		assert.Equal(t, errors.ErrInvalidVerificationToken, svc.ResetPassword(t.Context(), resetToken, "an0ther-Passw0rd!"))
+
+		m.users.AssertNumberOfCalls(t, "UpdatePassHash", 1)
+		m.assertExpectations(t)
+	})
+}
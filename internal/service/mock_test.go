@@ -0,0 +1,255 @@
+package service_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/geekswamp/zen/internal/base"
+	"github.com/geekswamp/zen/internal/crypto/key"
+	"github.com/geekswamp/zen/internal/http"
+	"github.com/geekswamp/zen/internal/mail"
+	"github.com/geekswamp/zen/internal/model"
+	"github.com/geekswamp/zen/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockTransactor runs the unit of work at once, without a transaction.
+type MockTransactor struct{}
+
+func (MockTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) Create(ctx context.Context, user model.User, passHash string) error {
+	args := m.Called(user, passHash)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.User, error) {
+	args := m.Called(id)
+	if user, ok := args.Get(0).(*model.User); ok {
+		return user, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockUserRepository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
+	args := m.Called(email)
+	if user, ok := args.Get(0).(*model.User); ok {
+		return user, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockUserRepository) FindPassHash(ctx context.Context, id uuid.UUID) (string, error) {
+	args := m.Called(id)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockUserRepository) List(ctx context.Context, filter repository.UserFilter, offset, limit int64) ([]model.User, int64, error) {
+	args := m.Called(filter, offset, limit)
+	return args.Get(0).([]model.User), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockUserRepository) ListByCursor(ctx context.Context, filter repository.UserFilter, cursor *http.Cursor, limit int64) (*base.KeysetResult[model.User], error) {
+	args := m.Called(filter, cursor, limit)
+	if result, ok := args.Get(0).(*base.KeysetResult[model.User]); ok {
+		return result, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockUserRepository) IsExist(ctx context.Context, user *model.User) (bool, error) {
+	args := m.Called(user)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) Update(ctx context.Context, id uuid.UUID, userMap base.UpdateMap) error {
+	args := m.Called(id, userMap)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateVersion(ctx context.Context, id uuid.UUID, version int64, userMap base.UpdateMap) error {
+	args := m.Called(id, version, userMap)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdatePassHash(ctx context.Context, id uuid.UUID, passHash string) error {
+	args := m.Called(id, passHash)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) SoftDelete(ctx context.Context, id uuid.UUID, version int64) error {
+	args := m.Called(id, version)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Restore(ctx context.Context, id uuid.UUID, version int64) error {
+	args := m.Called(id, version)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) HardDelete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+type MockRefreshTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockRefreshTokenRepository) Create(ctx context.Context, token model.RefreshToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) FindByHash(ctx context.Context, hash string) (*model.RefreshToken, error) {
+	args := m.Called(hash)
+	if token, ok := args.Get(0).(*model.RefreshToken); ok {
+		return token, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) Rotate(ctx context.Context, id uuid.UUID, next model.RefreshToken) error {
+	args := m.Called(id, next)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	args := m.Called(familyID)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) RevokeByUser(ctx context.Context, userID uuid.UUID) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+type MockRoleRepository struct {
+	mock.Mock
+}
+
+func (m *MockRoleRepository) FindByUser(ctx context.Context, userID uuid.UUID) ([]model.Role, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]model.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) FindByNames(ctx context.Context, names []string) ([]model.Role, error) {
+	args := m.Called(names)
+	return args.Get(0).([]model.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) AssignToUser(ctx context.Context, userID uuid.UUID, roles []model.Role) error {
+	args := m.Called(userID, roles)
+	return args.Error(0)
+}
+
+type MockVerificationTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockVerificationTokenRepository) Replace(ctx context.Context, token model.VerificationToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockVerificationTokenRepository) Consume(ctx context.Context, hash string, purpose model.TokenPurpose) (*model.VerificationToken, error) {
+	args := m.Called(hash, purpose)
+	if token, ok := args.Get(0).(*model.VerificationToken); ok {
+		return token, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) Record(ctx context.Context, entity model.Auditable, entityID uuid.UUID, action model.AuditAction, changes model.AuditChanges) error {
+	args := m.Called(entity.AuditType(), entityID, action, changes)
+	return args.Error(0)
+}
+
+func (m *MockAuditRepository) List(ctx context.Context, filter repository.AuditFilter, offset, limit int64) ([]model.AuditEvent, int64, error) {
+	args := m.Called(filter, offset, limit)
+	return args.Get(0).([]model.AuditEvent), args.Get(1).(int64), args.Error(2)
+}
+
+type MockStore struct {
+	mock.Mock
+}
+
+func (m *MockStore) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	args := m.Called(jti, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockStore) RevokeUser(ctx context.Context, userID uuid.UUID, at time.Time) error {
+	args := m.Called(userID, at)
+	return args.Error(0)
+}
+
+func (m *MockStore) IsRevoked(ctx context.Context, jti string, userID uuid.UUID, issuedAt time.Time) (bool, error) {
+	args := m.Called(jti, userID, issuedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+type MockMailer struct {
+	mock.Mock
+}
+
+func (m *MockMailer) Send(msg mail.Message) error {
+	args := m.Called(msg)
+	return args.Error(0)
+}
+
+// mocks holds the dependencies of the services under test.
+type mocks struct {
+	users      *MockUserRepository
+	tokens     *MockRefreshTokenRepository
+	roles      *MockRoleRepository
+	verifyRepo *MockVerificationTokenRepository
+	auditRepo  *MockAuditRepository
+	revoked    *MockStore
+	mailer     *MockMailer
+}
+
+func newMocks() mocks {
+	return mocks{
+		users:      new(MockUserRepository),
+		tokens:     new(MockRefreshTokenRepository),
+		roles:      new(MockRoleRepository),
+		verifyRepo: new(MockVerificationTokenRepository),
+		auditRepo:  new(MockAuditRepository),
+		revoked:    new(MockStore),
+		mailer:     new(MockMailer),
+	}
+}
+
+func (m mocks) assertExpectations(t *testing.T) {
+	t.Helper()
+
+	mock.AssertExpectationsForObjects(t, m.users, m.tokens, m.roles, m.verifyRepo, m.auditRepo, m.revoked, m.mailer)
+}
+
+func newKeyRing(t *testing.T) key.KeyRing {
+	t.Helper()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	provider, err := key.NewKeyPair("test", key.RS256, privateKey, &privateKey.PublicKey)
+	require.NoError(t, err)
+
+	return key.NewRing(provider)
+}
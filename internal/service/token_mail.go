@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/geekswamp/zen/internal/crypto/token"
+	"github.com/geekswamp/zen/internal/mail"
+	"github.com/geekswamp/zen/internal/model"
+	"github.com/geekswamp/zen/internal/repository"
+	"github.com/google/uuid"
+)
+
+// tokenMail describes an email carrying a link with a single-use token.
+type tokenMail struct {
+	purpose model.TokenPurpose
+	ttl     time.Duration
+	url     string
+	subject string
+	intro   string
+}
+
+// sendTokenMail stores a new token for the user and mails them the link carrying it,
+// invalidating any link of the same purpose sent before.
+func sendTokenMail(ctx context.Context, repo repository.VerificationTokenRepository, mailer mail.Mailer, userID uuid.UUID, email string, tm tokenMail) error {
+	plain, hash, err := token.NewOpaque()
+	if err != nil {
+		return err
+	}
+
+	vt := model.VerificationToken{
+		UserID:      userID,
+		Purpose:     tm.purpose,
+		TokenHash:   hash,
+		ExpiresTime: time.Now().Local().Add(tm.ttl).UnixMilli(),
+	}
+
+	if err := repo.Replace(ctx, vt); err != nil {
+		return err
+	}
+
+	return mailer.Send(mail.Message{
+		To:      email,
+		Subject: tm.subject,
+		Body:    fmt.Sprintf("%s\n\n%s?token=%s\n\nThe link expires in %s.\n", tm.intro, tm.url, url.QueryEscape(plain), tm.ttl),
+	})
+}
@@ -1,40 +1,82 @@
 package service
 
 import (
+	"context"
+	"slices"
 	"time"
 
 	"github.com/geekswamp/zen/configs"
 	"github.com/geekswamp/zen/internal/base"
+	"github.com/geekswamp/zen/internal/core"
 	"github.com/geekswamp/zen/internal/crypto/password"
+	"github.com/geekswamp/zen/internal/crypto/token"
+	"github.com/geekswamp/zen/internal/errors"
+	"github.com/geekswamp/zen/internal/http"
+	"github.com/geekswamp/zen/internal/logger"
+	"github.com/geekswamp/zen/internal/mail"
 	"github.com/geekswamp/zen/internal/model"
 	"github.com/geekswamp/zen/internal/repository"
+	"github.com/geekswamp/zen/internal/storage/revocation"
 	"github.com/google/uuid"
 )
 
 type UserService interface {
-	Create(fullName, email, passwordStr string, phone string, gender model.Gender) error
-	Get(id uuid.UUID) (*model.User, error)
-	Update(id uuid.UUID, userMap base.UpdateMap) error
-	Delete(id uuid.UUID) error
-	SoftDelete(id uuid.UUID) error
-	SetToActive(id uuid.UUID) error
-	SetToInactive(id uuid.UUID) error
+	Create(ctx context.Context, fullName, email, passwordStr string, phone string, gender model.Gender) error
+	Get(ctx context.Context, id uuid.UUID) (*model.User, error)
+	List(ctx context.Context, filter repository.UserFilter, offset, limit int64) ([]model.User, int64, error)
+	ListByCursor(ctx context.Context, filter repository.UserFilter, cursor *http.Cursor, limit int64) (*base.KeysetResult[model.User], error)
+	Update(ctx context.Context, id uuid.UUID, version int64, userMap base.UpdateMap) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	SoftDelete(ctx context.Context, id uuid.UUID, version int64) error
+	Restore(ctx context.Context, id uuid.UUID, version int64) error
+	SetToActive(ctx context.Context, id uuid.UUID, version int64) error
+	SetToInactive(ctx context.Context, id uuid.UUID, version int64) error
+	RevokeTokens(ctx context.Context, id uuid.UUID, version int64) error
+	SetRoles(ctx context.Context, id uuid.UUID, version int64, roleNames []string) error
+	VerifyEmail(ctx context.Context, verifyToken string) error
+	ChangePassword(ctx context.Context, id uuid.UUID, currentPassword, newPassword string) error
 }
 
 type UserServiceRepo struct {
-	repo repository.UserRepository
+	tx         base.Transactor
+	repo       repository.UserRepository
+	tokenRepo  repository.RefreshTokenRepository
+	roleRepo   repository.RoleRepository
+	verifyRepo repository.VerificationTokenRepository
+	auditRepo  repository.AuditRepository
+	revoked    revocation.Store
+	mailer     mail.Mailer
 }
 
-func NewUserService(repo repository.UserRepository) UserService {
-	return UserServiceRepo{repo: repo}
+func NewUserService(
+	tx base.Transactor,
+	repo repository.UserRepository,
+	tokenRepo repository.RefreshTokenRepository,
+	roleRepo repository.RoleRepository,
+	verifyRepo repository.VerificationTokenRepository,
+	auditRepo repository.AuditRepository,
+	revoked revocation.Store,
+	mailer mail.Mailer,
+) UserService {
+	return UserServiceRepo{
+		tx:         tx,
+		repo:       repo,
+		tokenRepo:  tokenRepo,
+		roleRepo:   roleRepo,
+		verifyRepo: verifyRepo,
+		auditRepo:  auditRepo,
+		revoked:    revoked,
+		mailer:     mailer,
+	}
 }
 
-func (s UserServiceRepo) Create(fullName, email, passwordStr string, phone string, gender model.Gender) error {
+func (s UserServiceRepo) Create(ctx context.Context, fullName, email, passwordStr string, phone string, gender model.Gender) error {
 	user := model.User{
 		FullName: fullName,
 		Email:    email,
 		Phone:    phone,
 		Gender:   gender,
+		Model:    base.Model{ID: uuid.New()},
 	}
 
 	pc := password.NewFromConfig(configs.Get())
@@ -43,29 +85,200 @@ func (s UserServiceRepo) Create(fullName, email, passwordStr string, phone strin
 		return err
 	}
 
-	return s.repo.Create(user, hash)
+	if err := s.repo.Create(ctx, user, hash); err != nil {
+		return err
+	}
+
+	// The account exists at this point, so a delivery failure must not fail the registration.
+	if err := s.sendVerification(ctx, user.ID, user.Email); err != nil {
+		log.Error(errors.ErrFailedToSendVerification.Error(), logger.ErrDetails(err), logger.RequestID(core.RequestIDFrom(ctx)))
+	}
+
+	return nil
+}
+
+func (s UserServiceRepo) Get(ctx context.Context, id uuid.UUID) (*model.User, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+func (s UserServiceRepo) List(ctx context.Context, filter repository.UserFilter, offset, limit int64) ([]model.User, int64, error) {
+	return s.repo.List(ctx, filter, offset, limit)
+}
+
+func (s UserServiceRepo) ListByCursor(ctx context.Context, filter repository.UserFilter, cursor *http.Cursor, limit int64) (*base.KeysetResult[model.User], error) {
+	return s.repo.ListByCursor(ctx, filter, cursor, limit)
+}
+
+// Update applies the changes while the user still has the given version, see base.CRUD.UpdateVersion.
+func (s UserServiceRepo) Update(ctx context.Context, id uuid.UUID, version int64, userMap base.UpdateMap) error {
+	return s.repo.UpdateVersion(ctx, id, version, userMap)
+}
+
+func (s UserServiceRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.repo.HardDelete(ctx, id)
 }
 
-func (s UserServiceRepo) Get(id uuid.UUID) (*model.User, error) {
-	return s.repo.FindByID(id)
+// SoftDelete deactivates the user while they still have the given version, revokes their
+// tokens and hides them from every query. Deleted users are purged for good once the
+// retention period has passed.
+func (s UserServiceRepo) SoftDelete(ctx context.Context, id uuid.UUID, version int64) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.Update(ctx, id, version, base.UpdateMap{"active": false}); err != nil {
+			return err
+		}
+
+		if err := s.revokeTokens(ctx, id); err != nil {
+			return err
+		}
+
+		// The update above checked the version and locks the row until the commit.
+		return s.repo.SoftDelete(ctx, id, base.AnyVersion)
+	})
+}
+
+// Restore brings back a soft deleted user while they still have the given version.
+// The user stays inactive until activated again.
+func (s UserServiceRepo) Restore(ctx context.Context, id uuid.UUID, version int64) error {
+	return s.repo.Restore(ctx, id, version)
 }
 
-func (s UserServiceRepo) Update(id uuid.UUID, userMap base.UpdateMap) error {
-	return s.repo.Update(id, userMap)
+func (s UserServiceRepo) SetToActive(ctx context.Context, id uuid.UUID, version int64) error {
+	return s.Update(ctx, id, version, base.UpdateMap{"active": true, "activated_time": time.Now().Local().UnixMilli()})
 }
 
-func (s UserServiceRepo) Delete(id uuid.UUID) error {
-	return s.repo.Delete(id)
+func (s UserServiceRepo) SetToInactive(ctx context.Context, id uuid.UUID, version int64) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.Update(ctx, id, version, base.UpdateMap{"active": false}); err != nil {
+			return err
+		}
+
+		return s.revokeTokens(ctx, id)
+	})
+}
+
+// RevokeTokens invalidates every access token issued to the user so far and revokes all
+// of their refresh tokens, while the user still has the given version. The version is bumped
+// and the revocation is audited.
+func (s UserServiceRepo) RevokeTokens(ctx context.Context, id uuid.UUID, version int64) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.Update(ctx, id, version, base.UpdateMap{}); err != nil {
+			return err
+		}
+
+		if err := s.revokeTokens(ctx, id); err != nil {
+			return err
+		}
+
+		return s.auditRepo.Record(ctx, model.User{}, id, model.AuditTokenRevoke, nil)
+	})
 }
 
-func (s UserServiceRepo) SoftDelete(id uuid.UUID) error {
-	return s.Update(id, base.UpdateMap{"active": false, "deleted_time": time.Now().Local().UnixMilli()})
+func (s UserServiceRepo) revokeTokens(ctx context.Context, id uuid.UUID) error {
+	if err := s.revoked.RevokeUser(ctx, id, time.Now()); err != nil {
+		return err
+	}
+
+	return s.tokenRepo.RevokeByUser(ctx, id)
 }
 
-func (s UserServiceRepo) SetToActive(id uuid.UUID) error {
-	return s.Update(id, base.UpdateMap{"active": true, "activated_time": time.Now().Local().UnixMilli()})
+// SetRoles replaces the roles of the user and bumps their version. The change applies
+// to tokens issued from the next login or refresh onwards. The role names before and
+// after the change are audited.
+func (s UserServiceRepo) SetRoles(ctx context.Context, id uuid.UUID, version int64, roleNames []string) error {
+	if _, err := s.repo.FindByID(ctx, id); err != nil {
+		return err
+	}
+
+	roleNames = slices.Compact(slices.Sorted(slices.Values(roleNames)))
+	roles, err := s.roleRepo.FindByNames(ctx, roleNames)
+	if err != nil {
+		return err
+	}
+
+	if len(roles) != len(roleNames) {
+		return errors.ErrRoleNotFound
+	}
+
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.Update(ctx, id, version, base.UpdateMap{}); err != nil {
+			return err
+		}
+
+		current, err := s.roleRepo.FindByUser(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := s.roleRepo.AssignToUser(ctx, id, roles); err != nil {
+			return err
+		}
+
+		before, _ := flattenRoles(current)
+		slices.Sort(before)
+
+		return s.auditRepo.Record(ctx, model.User{}, id, model.AuditRoleAssign, model.AuditChanges{
+			"roles": {Before: before, After: roleNames},
+		})
+	})
+}
+
+// VerifyEmail consumes an email verification token and activates its user.
+func (s UserServiceRepo) VerifyEmail(ctx context.Context, verifyToken string) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		vt, err := s.verifyRepo.Consume(ctx, token.HashOpaque(verifyToken), model.EmailVerification)
+		if err != nil {
+			return err
+		}
+
+		return s.SetToActive(ctx, vt.UserID, base.AnyVersion)
+	})
+}
+
+// ChangePassword replaces the password of the user after checking the current one.
+// Every session of the user is revoked, including the one making the change, and the
+// change is audited.
+func (s UserServiceRepo) ChangePassword(ctx context.Context, id uuid.UUID, currentPassword, newPassword string) error {
+	encoded, err := s.repo.FindPassHash(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	pc := password.NewFromConfig(configs.Get())
+	result, err := pc.Verify([]byte(currentPassword), encoded)
+	if err != nil {
+		return err
+	}
+
+	if !result.Valid {
+		return errors.ErrWrongPassword
+	}
+
+	hash, err := pc.Generate([]byte(newPassword))
+	if err != nil {
+		return err
+	}
+
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdatePassHash(ctx, id, hash); err != nil {
+			return err
+		}
+
+		if err := s.revokeTokens(ctx, id); err != nil {
+			return err
+		}
+
+		return s.auditRepo.Record(ctx, model.User{}, id, model.AuditPasswordChange, nil)
+	})
 }
 
-func (s UserServiceRepo) SetToInactive(id uuid.UUID) error {
-	return s.Update(id, base.UpdateMap{"active": false})
+// sendVerification mails a new verification link to the user, invalidating any link sent before.
+func (s UserServiceRepo) sendVerification(ctx context.Context, userID uuid.UUID, email string) error {
+	cfg := configs.Get().Verification
+	return sendTokenMail(ctx, s.verifyRepo, s.mailer, userID, email, tokenMail{
+		purpose: model.EmailVerification,
+		ttl:     cfg.TokenTTL,
+		url:     cfg.URL,
+		subject: "Verify your email address",
+		intro:   "Open the link below to verify your email address and activate your account.",
+	})
 }
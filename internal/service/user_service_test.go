@@ -0,0 +1,107 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/geekswamp/zen/configs"
+	"github.com/geekswamp/zen/internal/base"
+	"github.com/geekswamp/zen/internal/crypto/password"
+	"github.com/geekswamp/zen/internal/errors"
+	"github.com/geekswamp/zen/internal/model"
+	"github.com/geekswamp/zen/internal/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newUserService(m mocks) service.UserService {
+	return service.NewUserService(MockTransactor{}, m.users, m.tokens, m.roles, m.verifyRepo, m.auditRepo, m.revoked, m.mailer)
+}
+
+func TestSetRolesIsAudited(t *testing.T) {
+	id := uuid.New()
+	admin := model.Role{Model: base.Model{ID: uuid.New()}, Name: "admin"}
+	viewer := model.Role{Model: base.Model{ID: uuid.New()}, Name: "viewer"}
+	editor := model.Role{Model: base.Model{ID: uuid.New()}, Name: "editor"}
+
+	m := newMocks()
+	m.users.On("FindByID", id).Return(&model.User{Model: base.Model{ID: id}}, nil)
+	m.roles.On("FindByNames", []string{"admin", "editor"}).Return([]model.Role{admin, editor}, nil)
+	m.users.On("UpdateVersion", id, int64(3), base.UpdateMap{}).Return(nil)
+	m.roles.On("FindByUser", id).Return([]model.Role{viewer, editor}, nil)
+	m.roles.On("AssignToUser", id, []model.Role{admin, editor}).Return(nil)
+	m.auditRepo.On("Record", "user", id, model.AuditRoleAssign, model.AuditChanges{
+		"roles": {Before: []string{"editor", "viewer"}, After: []string{"admin", "editor"}},
+	}).Return(nil)
+
+	err := newUserService(m).SetRoles(t.Context(), id, 3, []string{"editor", "admin", "editor"})
+	assert.NoError(t, err)
+	m.assertExpectations(t)
+}
+
+func TestRevokeTokensIsAudited(t *testing.T) {
+	id := uuid.New()
+
+	m := newMocks()
+	m.users.On("UpdateVersion", id, int64(2), base.UpdateMap{}).Return(nil)
+	m.revoked.On("RevokeUser", id, mock.Anything).Return(nil)
+	m.tokens.On("RevokeByUser", id).Return(nil)
+	m.auditRepo.On("Record", "user", id, model.AuditTokenRevoke, model.AuditChanges(nil)).Return(nil)
+
+	err := newUserService(m).RevokeTokens(t.Context(), id, 2)
+	assert.NoError(t, err)
+	m.assertExpectations(t)
+}
+
+func TestChangePassword(t *testing.T) {
+	const currentPassword = "curr3nt-Passw0rd!"
+
+	id := uuid.New()
+	pc := password.NewFromConfig(configs.Get())
+	hash, err := pc.Generate([]byte(currentPassword))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		password string
+		setup    func(m mocks)
+		wantErr  error
+	}{
+		{
+			name:     "Changed Revokes Every Session",
+			password: currentPassword,
+			setup: func(m mocks) {
+				m.users.On("FindPassHash", id).Return(hash, nil)
+				m.users.On("UpdatePassHash", id, mock.MatchedBy(func(next string) bool { return next != hash })).Return(nil)
+				m.revoked.On("RevokeUser", id, mock.Anything).Return(nil)
+				m.tokens.On("RevokeByUser", id).Return(nil)
+				m.auditRepo.On("Record", "user", id, model.AuditPasswordChange, model.AuditChanges(nil)).Return(nil)
+			},
+		},
+		{
+			name:     "Wrong Current Password",
+			password: "wr0ng-Passw0rd!",
+			setup: func(m mocks) {
+				m.users.On("FindPassHash", id).Return(hash, nil)
+			},
+			wantErr: errors.ErrWrongPassword,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newMocks()
+			tc.setup(m)
+
+			err := newUserService(m).ChangePassword(t.Context(), id, tc.password, "n3w-Passw0rd!")
+			assert.Equal(t, tc.wantErr, err)
+			if tc.wantErr != nil {
+				m.users.AssertNotCalled(t, "UpdatePassHash", mock.Anything, mock.Anything)
+				m.revoked.AssertNotCalled(t, "RevokeUser", mock.Anything, mock.Anything)
+			}
+
+			m.assertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package migration
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/geekswamp/zen/internal/errors"
+	"github.com/geekswamp/zen/internal/logger"
+	"gorm.io/gorm"
+)
+
+var log = logger.New()
+
+//go:embed sql/*.sql
+var sqlFiles embed.FS
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Func is a migration step written in Go. It runs inside the transaction of its migration.
+type Func func(tx *gorm.DB) error
+
+// Migration is a versioned schema change. Versions are applied in ascending order
+// and each one runs in its own transaction together with its bookkeeping.
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       Func
+	Down     Func
+	Checksum string
+}
+
+var goMigrations []Migration
+
+// Register adds a migration written in Go. It is meant to be called from init;
+// versions taken twice are reported by Load. Its checksum covers the version and
+// name only, since code cannot be hashed.
+func Register(version int64, name string, up, down Func) {
+	m := Migration{Version: version, Name: name, Up: up, Down: down}
+	m.Checksum = checksum(fmt.Sprintf("go:%d:%s", version, name))
+	goMigrations = append(goMigrations, m)
+}
+
+// Load returns every migration of the binary, embedded SQL files and Go migrations,
+// sorted by version.
+func Load() ([]Migration, error) {
+	byVersion := map[int64]*Migration{}
+	sources := map[int64]map[string][]byte{}
+
+	entries, err := fs.ReadDir(sqlFiles, "sql")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("%w: %s", errors.ErrInvalidMigrationFile, entry.Name())
+		}
+
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		content, err := sqlFiles.ReadFile(path.Join("sql", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+			sources[version] = map[string][]byte{}
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("%w: %d", errors.ErrDuplicateMigration, version)
+		}
+
+		sources[version][match[3]] = content
+		switch match[3] {
+		case "up":
+			m.Up = execSQL(string(content))
+		case "down":
+			m.Down = execSQL(string(content))
+		}
+	}
+
+	for version, m := range byVersion {
+		m.Checksum = checksum(string(sources[version]["up"]) + "\x00" + string(sources[version]["down"]))
+	}
+
+	for _, m := range goMigrations {
+		if _, ok := byVersion[m.Version]; ok {
+			return nil, fmt.Errorf("%w: %d", errors.ErrDuplicateMigration, m.Version)
+		}
+		byVersion[m.Version] = &m
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == nil {
+			return nil, fmt.Errorf("%w: %d has no up step", errors.ErrInvalidMigrationFile, m.Version)
+		}
+		migrations = append(migrations, *m)
+	}
+
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+
+	return migrations, nil
+}
+
+func execSQL(query string) Func {
+	return func(tx *gorm.DB) error {
+		return tx.Exec(query).Error
+	}
+}
+
+func checksum(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
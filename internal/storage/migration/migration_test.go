@@ -0,0 +1,58 @@
+package migration_test
+
+import (
+	"testing"
+
+	"github.com/geekswamp/zen/internal/errors"
+	"github.com/geekswamp/zen/internal/storage/migration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestLoad(t *testing.T) {
+	first, err := migration.Load()
+	require.NoError(t, err)
+	require.NotEmpty(t, first)
+
+	t.Run("Embedded SQL", func(t *testing.T) {
+		assert.Equal(t, int64(1), first[0].Version)
+		assert.Equal(t, "init", first[0].Name)
+		assert.NotNil(t, first[0].Up)
+		assert.NotNil(t, first[0].Down)
+		assert.Len(t, first[0].Checksum, 64)
+
+		for i := 1; i < len(first); i++ {
+			assert.Less(t, first[i-1].Version, first[i].Version)
+		}
+	})
+
+	t.Run("Stable Checksums", func(t *testing.T) {
+		second, err := migration.Load()
+		require.NoError(t, err)
+
+		for i := range first {
+			assert.Equal(t, first[i].Checksum, second[i].Checksum)
+		}
+	})
+
+	t.Run("Go Migration", func(t *testing.T) {
+		noop := func(_ *gorm.DB) error { return nil }
+		migration.Register(900_000, "go_step", noop, nil)
+
+		migrations, err := migration.Load()
+		require.NoError(t, err)
+
+		last := migrations[len(migrations)-1]
+		assert.Equal(t, int64(900_000), last.Version)
+		assert.Nil(t, last.Down)
+		assert.Len(t, last.Checksum, 64)
+	})
+
+	t.Run("Duplicate Version", func(t *testing.T) {
+		migration.Register(1, "taken", func(_ *gorm.DB) error { return nil }, nil)
+
+		_, err := migration.Load()
+		assert.ErrorIs(t, err, errors.ErrDuplicateMigration)
+	})
+}
@@ -0,0 +1,265 @@
+package migration
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/geekswamp/zen/internal/errors"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// _LockKey identifies the Postgres advisory lock held while migrating, so that
+// replicas starting at the same time apply migrations one after the other.
+const _LockKey int64 = 7_236_842_119_046_502_401
+
+const _CreateTableSQL = `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version      BIGINT PRIMARY KEY,
+    name         VARCHAR NOT NULL,
+    checksum     VARCHAR NOT NULL,
+    applied_time BIGINT NOT NULL
+)`
+
+// Record is a row of the schema_migrations table.
+type Record struct {
+	Version     int64  `gorm:"column:version;primaryKey"`
+	Name        string `gorm:"column:name"`
+	Checksum    string `gorm:"column:checksum"`
+	AppliedTime int64  `gorm:"column:applied_time"`
+}
+
+func (Record) TableName() string {
+	return "schema_migrations"
+}
+
+// Status describes a migration known to the binary or recorded in the database.
+type Status struct {
+	Version     int64
+	Name        string
+	Applied     bool
+	AppliedTime int64
+	Modified    bool // applied with a different checksum
+	Missing     bool // applied but unknown to this binary
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// New creates a migrator for the migrations embedded in the binary.
+func New(db *gorm.DB) (*Migrator, error) {
+	migrations, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Up applies every pending migration and returns how many were applied.
+func (m *Migrator) Up() (int, error) {
+	applied := 0
+	err := m.withLock(func(conn *gorm.DB) error {
+		records, err := m.verify(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mig := range m.migrations {
+			if _, ok := records[mig.Version]; ok {
+				continue
+			}
+
+			if err := m.apply(conn, mig); err != nil {
+				return err
+			}
+			applied++
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down rolls back the given number of most recently applied migrations and returns
+// how many were rolled back.
+func (m *Migrator) Down(steps int) (int, error) {
+	reverted := 0
+	err := m.withLock(func(conn *gorm.DB) error {
+		records, err := m.verify(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mig := range m.latest(records, steps) {
+			if err := m.revert(conn, mig); err != nil {
+				return err
+			}
+			reverted++
+		}
+
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Redo rolls back the most recently applied migration and applies it again.
+func (m *Migrator) Redo() error {
+	return m.withLock(func(conn *gorm.DB) error {
+		records, err := m.verify(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mig := range m.latest(records, 1) {
+			if err := m.revert(conn, mig); err != nil {
+				return err
+			}
+
+			if err := m.apply(conn, mig); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Status lists every migration with its state, including applied ones this binary does not know.
+func (m *Migrator) Status() ([]Status, error) {
+	records := []Record{}
+	if m.db.Migrator().HasTable(&Record{}) {
+		if err := m.db.Order("version").Find(&records).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	byVersion := map[int64]Record{}
+	for _, r := range records {
+		byVersion[r.Version] = r
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		s := Status{Version: mig.Version, Name: mig.Name}
+		if r, ok := byVersion[mig.Version]; ok {
+			s.Applied = true
+			s.AppliedTime = r.AppliedTime
+			s.Modified = r.Checksum != mig.Checksum
+			delete(byVersion, mig.Version)
+		}
+		statuses = append(statuses, s)
+	}
+
+	for _, r := range records {
+		if _, ok := byVersion[r.Version]; ok {
+			statuses = append(statuses, Status{Version: r.Version, Name: r.Name, Applied: true, AppliedTime: r.AppliedTime, Missing: true})
+		}
+	}
+
+	slices.SortFunc(statuses, func(a, b Status) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+
+	return statuses, nil
+}
+
+// withLock runs fn on a single connection holding the migration advisory lock.
+func (m *Migrator) withLock(fn func(conn *gorm.DB) error) error {
+	return m.db.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", _LockKey).Error; err != nil {
+			return err
+		}
+		defer conn.Exec("SELECT pg_advisory_unlock(?)", _LockKey)
+
+		if err := conn.Exec(_CreateTableSQL).Error; err != nil {
+			return err
+		}
+
+		return fn(conn)
+	})
+}
+
+// verify loads the applied migrations and checks that each one is known to the binary
+// and unchanged since it was applied.
+func (m *Migrator) verify(conn *gorm.DB) (map[int64]Record, error) {
+	records := []Record{}
+	if err := conn.Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	known := map[int64]Migration{}
+	for _, mig := range m.migrations {
+		known[mig.Version] = mig
+	}
+
+	byVersion := map[int64]Record{}
+	for _, r := range records {
+		mig, ok := known[r.Version]
+		if !ok {
+			return nil, fmt.Errorf("%w: %d_%s", errors.ErrMigrationUnknown, r.Version, r.Name)
+		}
+
+		if mig.Checksum != r.Checksum {
+			return nil, fmt.Errorf("%w: %d_%s", errors.ErrMigrationChecksum, r.Version, r.Name)
+		}
+
+		byVersion[r.Version] = r
+	}
+
+	return byVersion, nil
+}
+
+// latest returns up to n applied migrations, most recent first.
+func (m *Migrator) latest(records map[int64]Record, n int) []Migration {
+	latest := []Migration{}
+	for _, mig := range slices.Backward(m.migrations) {
+		if len(latest) == n {
+			break
+		}
+
+		if _, ok := records[mig.Version]; ok {
+			latest = append(latest, mig)
+		}
+	}
+
+	return latest
+}
+
+func (m *Migrator) apply(conn *gorm.DB, mig Migration) error {
+	log.Info("Applying migration", zap.Int64("version", mig.Version), zap.String("name", mig.Name))
+
+	return conn.Transaction(func(tx *gorm.DB) error {
+		if err := mig.Up(tx); err != nil {
+			return err
+		}
+
+		return tx.Create(&Record{
+			Version:     mig.Version,
+			Name:        mig.Name,
+			Checksum:    mig.Checksum,
+			AppliedTime: time.Now().Local().UnixMilli(),
+		}).Error
+	})
+}
+
+func (m *Migrator) revert(conn *gorm.DB, mig Migration) error {
+	if mig.Down == nil {
+		return fmt.Errorf("%w: %d_%s", errors.ErrMigrationIrreversible, mig.Version, mig.Name)
+	}
+
+	log.Info("Reverting migration", zap.Int64("version", mig.Version), zap.String("name", mig.Name))
+
+	return conn.Transaction(func(tx *gorm.DB) error {
+		if err := mig.Down(tx); err != nil {
+			return err
+		}
+
+		return tx.Delete(&Record{}, mig.Version).Error
+	})
+}
@@ -0,0 +1,128 @@
+-- Baseline schema. Every statement is idempotent so that databases created by the
+-- former AutoMigrate on boot can be brought under version control as they are.
+
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+CREATE TABLE IF NOT EXISTS users (
+    id             UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_time   BIGINT,
+    updated_time   BIGINT,
+    deleted_time   BIGINT,
+    full_name      VARCHAR NOT NULL,
+    email          VARCHAR NOT NULL,
+    phone          VARCHAR,
+    active         BOOLEAN NOT NULL DEFAULT FALSE,
+    gender         SMALLINT NOT NULL,
+    activated_time BIGINT
+);
+
+-- The email and phone indexes used to cover soft deleted users as well.
+DROP INDEX IF EXISTS idx_users_email;
+DROP INDEX IF EXISTS idx_users_phone;
+
+CREATE INDEX IF NOT EXISTS idx_users_keyset ON users (created_time, id);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_time ON users (deleted_time);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_alive ON users (email) WHERE deleted_time IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_phone_alive ON users (phone) WHERE deleted_time IS NULL;
+
+CREATE TABLE IF NOT EXISTS user_pass_hashes (
+    user_id   UUID PRIMARY KEY,
+    pass_hash VARCHAR NOT NULL,
+    CONSTRAINT fk_users_pass_hash FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id           UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_time BIGINT,
+    updated_time BIGINT,
+    deleted_time BIGINT,
+    user_id      UUID NOT NULL,
+    family_id    UUID NOT NULL,
+    token_hash   VARCHAR NOT NULL,
+    expires_time BIGINT NOT NULL,
+    used_time    BIGINT,
+    revoked_time BIGINT,
+    CONSTRAINT fk_users_refresh_tokens FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_keyset ON refresh_tokens (created_time, id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_deleted_time ON refresh_tokens (deleted_time);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens (user_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens (family_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refresh_tokens_token_hash ON refresh_tokens (token_hash);
+
+CREATE TABLE IF NOT EXISTS verification_tokens (
+    id           UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_time BIGINT,
+    updated_time BIGINT,
+    deleted_time BIGINT,
+    user_id      UUID NOT NULL,
+    purpose      VARCHAR NOT NULL,
+    token_hash   VARCHAR NOT NULL,
+    expires_time BIGINT NOT NULL,
+    used_time    BIGINT,
+    CONSTRAINT fk_users_verification_tokens FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_verification_tokens_keyset ON verification_tokens (created_time, id);
+CREATE INDEX IF NOT EXISTS idx_verification_tokens_deleted_time ON verification_tokens (deleted_time);
+CREATE INDEX IF NOT EXISTS idx_verification_tokens_user_id ON verification_tokens (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_verification_tokens_token_hash ON verification_tokens (token_hash);
+
+CREATE TABLE IF NOT EXISTS roles (
+    id           UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_time BIGINT,
+    updated_time BIGINT,
+    deleted_time BIGINT,
+    name         VARCHAR NOT NULL,
+    description  VARCHAR
+);
+
+CREATE INDEX IF NOT EXISTS idx_roles_keyset ON roles (created_time, id);
+CREATE INDEX IF NOT EXISTS idx_roles_deleted_time ON roles (deleted_time);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_roles_name ON roles (name);
+
+CREATE TABLE IF NOT EXISTS permissions (
+    id           UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_time BIGINT,
+    updated_time BIGINT,
+    deleted_time BIGINT,
+    name         VARCHAR NOT NULL,
+    description  VARCHAR
+);
+
+CREATE INDEX IF NOT EXISTS idx_permissions_keyset ON permissions (created_time, id);
+CREATE INDEX IF NOT EXISTS idx_permissions_deleted_time ON permissions (deleted_time);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_permissions_name ON permissions (name);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id       UUID NOT NULL,
+    permission_id UUID NOT NULL,
+    PRIMARY KEY (role_id, permission_id),
+    CONSTRAINT fk_role_permissions_role FOREIGN KEY (role_id) REFERENCES roles (id) ON DELETE CASCADE,
+    CONSTRAINT fk_role_permissions_permission FOREIGN KEY (permission_id) REFERENCES permissions (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id UUID NOT NULL,
+    role_id UUID NOT NULL,
+    PRIMARY KEY (user_id, role_id),
+    CONSTRAINT fk_user_roles_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_roles_role FOREIGN KEY (role_id) REFERENCES roles (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    jti          VARCHAR PRIMARY KEY,
+    expires_time BIGINT NOT NULL,
+    created_time BIGINT
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_time ON revoked_tokens (expires_time);
+
+CREATE TABLE IF NOT EXISTS user_revocations (
+    user_id      UUID PRIMARY KEY,
+    revoked_time BIGINT NOT NULL,
+    expires_time BIGINT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_revocations_expires_time ON user_revocations (expires_time);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS seed_history (
+    name          VARCHAR PRIMARY KEY,
+    env           VARCHAR NOT NULL,
+    run_count     INTEGER NOT NULL DEFAULT 1,
+    last_run_time BIGINT NOT NULL
+);
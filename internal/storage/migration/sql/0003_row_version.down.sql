@@ -0,0 +1,5 @@
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS version;
+ALTER TABLE permissions DROP COLUMN IF EXISTS version;
+ALTER TABLE roles DROP COLUMN IF EXISTS version;
+ALTER TABLE verification_tokens DROP COLUMN IF EXISTS version;
+ALTER TABLE users DROP COLUMN IF EXISTS version;
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS audit_events (
+    id           UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    actor_id     UUID,
+    request_id   VARCHAR,
+    entity_type  VARCHAR NOT NULL,
+    entity_id    VARCHAR NOT NULL,
+    action       VARCHAR NOT NULL,
+    changes      JSONB NOT NULL,
+    created_time BIGINT
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor_id ON audit_events (actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_entity ON audit_events (entity_type, entity_id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_created_time ON audit_events (created_time);
+
+-- Databases seeded before this migration already recorded the roles seeder as run,
+-- so the admin role is granted the new permission here.
+INSERT INTO permissions (name, description, created_time, updated_time)
+VALUES ('audit:read', 'View the audit log', (EXTRACT(EPOCH FROM NOW()) * 1000)::BIGINT, (EXTRACT(EPOCH FROM NOW()) * 1000)::BIGINT)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r, permissions p
+WHERE r.name = 'admin' AND p.name = 'audit:read'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,271 @@
+package postgres
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/geekswamp/zen/internal/core"
+	"github.com/geekswamp/zen/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// _AuditBeforeKey keys the rows captured before an update or delete in the statement settings.
+const _AuditBeforeKey = "audit:before"
+
+// registerAudit records creates, updates and deletes of model.Auditable models as
+// model.AuditEvent rows. The events are written in the transaction of the mutation, and
+// carry the user session and request id found in the context of the statement.
+func registerAudit(db *gorm.DB) error {
+	cb := db.Callback()
+
+	if err := cb.Create().After("gorm:create").Before("gorm:commit_or_rollback_transaction").
+		Register("audit:create", auditCreate); err != nil {
+		return err
+	}
+
+	if err := cb.Update().After("gorm:begin_transaction").Before("gorm:update").
+		Register("audit:capture_update", auditCapture); err != nil {
+		return err
+	}
+
+	if err := cb.Update().After("gorm:update").Before("gorm:commit_or_rollback_transaction").
+		Register("audit:update", auditUpdate); err != nil {
+		return err
+	}
+
+	if err := cb.Delete().After("gorm:begin_transaction").Before("gorm:delete").
+		Register("audit:capture_delete", auditCapture); err != nil {
+		return err
+	}
+
+	return cb.Delete().After("gorm:delete").Before("gorm:commit_or_rollback_transaction").
+		Register("audit:delete", auditDelete)
+}
+
+func auditCreate(db *gorm.DB) {
+	entityType, ok := auditable(db)
+	if !ok || db.RowsAffected == 0 {
+		return
+	}
+
+	after, err := auditRows(db, primaryKeys(db))
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+
+	events := make([]model.AuditEvent, 0, len(after))
+	for id, row := range after {
+		events = append(events, newAuditEvent(db, entityType, id, model.AuditCreate, model.DiffAudit(nil, row)))
+	}
+
+	writeAudit(db, events)
+}
+
+// auditCapture loads the rows an update or delete is about to change.
+func auditCapture(db *gorm.DB) {
+	if _, ok := auditable(db); !ok {
+		return
+	}
+
+	ids := primaryKeys(db)
+	if where, ok := db.Statement.Clauses["WHERE"]; ok {
+		matched := []any{}
+		err := auditQuery(db).Clauses(where.Expression).Pluck(primaryKey(db), &matched).Error
+		if err != nil {
+			db.AddError(err)
+			return
+		}
+		ids = matched
+	}
+
+	before, err := auditRows(db, ids)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+
+	db.Statement.Settings.Store(_AuditBeforeKey, before)
+}
+
+func auditUpdate(db *gorm.DB) {
+	entityType, before, ok := captured(db)
+	if !ok {
+		return
+	}
+
+	ids := make([]any, 0, len(before))
+	for id := range before {
+		ids = append(ids, id)
+	}
+
+	after, err := auditRows(db, ids)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+
+	events := []model.AuditEvent{}
+	for id, row := range after {
+		changes := model.DiffAudit(before[id], row)
+		if len(changes) == 0 {
+			continue
+		}
+
+		action := model.AuditUpdate
+		if before[id]["deleted_time"] != nil && row["deleted_time"] == nil {
+			action = model.AuditRestore
+		}
+
+		events = append(events, newAuditEvent(db, entityType, id, action, changes))
+	}
+
+	writeAudit(db, events)
+}
+
+func auditDelete(db *gorm.DB) {
+	entityType, before, ok := captured(db)
+	if !ok {
+		return
+	}
+
+	ids := make([]any, 0, len(before))
+	for id := range before {
+		ids = append(ids, id)
+	}
+
+	// Soft deleted rows are still there, purged ones are gone.
+	after, err := auditRows(db, ids)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+
+	events := []model.AuditEvent{}
+	for id, row := range before {
+		action := model.AuditDelete
+		if _, ok := after[id]; !ok {
+			action = model.AuditPurge
+		}
+
+		changes := model.DiffAudit(row, after[id])
+		if len(changes) == 0 {
+			continue
+		}
+
+		events = append(events, newAuditEvent(db, entityType, id, action, changes))
+	}
+
+	writeAudit(db, events)
+}
+
+// auditable returns the entity type of the statement model when it is model.Auditable.
+func auditable(db *gorm.DB) (string, bool) {
+	if db.Error != nil || db.DryRun || db.Statement.Schema == nil {
+		return "", false
+	}
+
+	a, ok := reflect.New(db.Statement.Schema.ModelType).Interface().(model.Auditable)
+	if !ok {
+		return "", false
+	}
+
+	return a.AuditType(), true
+}
+
+func captured(db *gorm.DB) (string, map[string]map[string]any, bool) {
+	entityType, ok := auditable(db)
+	if !ok || db.RowsAffected == 0 {
+		return "", nil, false
+	}
+
+	value, ok := db.Statement.Settings.Load(_AuditBeforeKey)
+	if !ok {
+		return "", nil, false
+	}
+
+	before := value.(map[string]map[string]any)
+	return entityType, before, len(before) > 0
+}
+
+// auditQuery starts a query on the table of the statement, in its transaction and
+// including soft deleted rows, that does not trigger any callback of its own.
+func auditQuery(db *gorm.DB) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Table(db.Statement.Table)
+}
+
+// auditRows loads the rows with the given primary keys as column maps keyed by primary key.
+func auditRows(db *gorm.DB, ids []any) (map[string]map[string]any, error) {
+	rows := map[string]map[string]any{}
+	if len(ids) == 0 {
+		return rows, nil
+	}
+
+	found := []map[string]any{}
+	column := clause.Column{Name: primaryKey(db)}
+	if err := auditQuery(db).Where(clause.IN{Column: column, Values: ids}).Find(&found).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range found {
+		rows[fmt.Sprint(row[primaryKey(db)])] = row
+	}
+
+	return rows, nil
+}
+
+func primaryKey(db *gorm.DB) string {
+	return db.Statement.Schema.PrioritizedPrimaryField.DBName
+}
+
+// primaryKeys returns the non-zero primary keys of the statement value, e.g. of created rows.
+func primaryKeys(db *gorm.DB) []any {
+	field := db.Statement.Schema.PrioritizedPrimaryField
+	rv := reflect.Indirect(db.Statement.ReflectValue)
+
+	values := []reflect.Value{}
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := range rv.Len() {
+			values = append(values, reflect.Indirect(rv.Index(i)))
+		}
+	case reflect.Struct:
+		values = append(values, rv)
+	}
+
+	ids := []any{}
+	for _, v := range values {
+		if id, zero := field.ValueOf(db.Statement.Context, v); !zero {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+func newAuditEvent(db *gorm.DB, entityType, entityID string, action model.AuditAction, changes model.AuditChanges) model.AuditEvent {
+	event := model.AuditEvent{
+		RequestID:  core.RequestIDFrom(db.Statement.Context),
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		Changes:    changes,
+	}
+
+	if session := core.UserSessionFrom(db.Statement.Context); session != nil {
+		event.ActorID = &session.ID
+	}
+
+	return event
+}
+
+func writeAudit(db *gorm.DB, events []model.AuditEvent) {
+	if len(events) == 0 {
+		return
+	}
+
+	if err := db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Create(&events).Error; err != nil {
+		db.AddError(err)
+	}
+}
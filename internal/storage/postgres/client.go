@@ -83,6 +83,10 @@ func connect(config configs.Config) (*gorm.DB, error) {
 		return nil, errors.ErrFailedToConnectDB
 	}
 
+	if err := registerAudit(db); err != nil {
+		return nil, err
+	}
+
 	sqlDB, err := db.DB()
 	if err != nil {
 		return nil, err
@@ -0,0 +1,109 @@
+package revocation
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Cache is an in-process Store that remembers lookups of another Store for a fixed TTL.
+// Revocations made through the cache are written to the underlying store and applied
+// locally at once; revocations made by other instances become visible after the TTL.
+type Cache struct {
+	store     Store
+	ttl       time.Duration
+	mu        sync.Mutex
+	entries   map[string]cacheEntry
+	lastSweep time.Time
+}
+
+type cacheEntry struct {
+	userID    uuid.UUID
+	revoked   bool
+	expiresAt time.Time
+}
+
+// NewCache wraps the given store with an in-process TTL cache.
+func NewCache(store Store, ttl time.Duration) Store {
+	return &Cache{
+		store:     store,
+		ttl:       ttl,
+		entries:   map[string]cacheEntry{},
+		lastSweep: time.Now(),
+	}
+}
+
+func (c *Cache) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	if err := c.store.Revoke(ctx, jti, expiresAt); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := c.entries[jti]
+	entry.revoked = true
+	entry.expiresAt = expiresAt
+	c.entries[jti] = entry
+
+	return nil
+}
+
+func (c *Cache) RevokeUser(ctx context.Context, userID uuid.UUID, at time.Time) error {
+	if err := c.store.RevokeUser(ctx, userID, at); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for jti, entry := range c.entries {
+		if entry.userID == userID && !entry.revoked {
+			delete(c.entries, jti)
+		}
+	}
+
+	return nil
+}
+
+func (c *Cache) IsRevoked(ctx context.Context, jti string, userID uuid.UUID, issuedAt time.Time) (bool, error) {
+	now := time.Now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[jti]
+	c.mu.Unlock()
+
+	if ok && now.Before(entry.expiresAt) {
+		return entry.revoked, nil
+	}
+
+	revoked, err := c.store.IsRevoked(ctx, jti, userID, issuedAt)
+	if err != nil {
+		return false, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[jti] = cacheEntry{userID: userID, revoked: revoked, expiresAt: now.Add(c.ttl)}
+	c.sweep(now)
+
+	return revoked, nil
+}
+
+// sweep drops expired entries at most once per TTL. The caller must hold the lock.
+func (c *Cache) sweep(now time.Time) {
+	if now.Sub(c.lastSweep) < c.ttl {
+		return
+	}
+
+	for jti, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, jti)
+		}
+	}
+
+	c.lastSweep = now
+}
@@ -0,0 +1,97 @@
+package revocation_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/geekswamp/zen/internal/storage/revocation"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockStore struct {
+	mock.Mock
+}
+
+func (m *MockStore) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	args := m.Called(jti, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockStore) RevokeUser(ctx context.Context, userID uuid.UUID, at time.Time) error {
+	args := m.Called(userID, at)
+	return args.Error(0)
+}
+
+func (m *MockStore) IsRevoked(ctx context.Context, jti string, userID uuid.UUID, issuedAt time.Time) (bool, error) {
+	args := m.Called(jti, userID, issuedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestCacheIsRevoked(t *testing.T) {
+	userID := uuid.New()
+	issuedAt := time.Now()
+
+	mockStore := new(MockStore)
+	mockStore.On("IsRevoked", "jti", userID, issuedAt).Return(false, nil).Once()
+
+	cache := revocation.NewCache(mockStore, time.Minute)
+
+	for range 3 {
+		revoked, err := cache.IsRevoked(t.Context(), "jti", userID, issuedAt)
+		require.NoError(t, err)
+		require.False(t, revoked)
+	}
+
+	mockStore.AssertNumberOfCalls(t, "IsRevoked", 1)
+}
+
+func TestCacheRevoke(t *testing.T) {
+	userID := uuid.New()
+	issuedAt := time.Now()
+	expiresAt := issuedAt.Add(time.Hour)
+
+	mockStore := new(MockStore)
+	mockStore.On("IsRevoked", "jti", userID, issuedAt).Return(false, nil).Once()
+	mockStore.On("Revoke", "jti", expiresAt).Return(nil).Once()
+
+	cache := revocation.NewCache(mockStore, time.Minute)
+
+	revoked, err := cache.IsRevoked(t.Context(), "jti", userID, issuedAt)
+	require.NoError(t, err)
+	require.False(t, revoked)
+
+	require.NoError(t, cache.Revoke(t.Context(), "jti", expiresAt))
+
+	revoked, err = cache.IsRevoked(t.Context(), "jti", userID, issuedAt)
+	require.NoError(t, err)
+	require.True(t, revoked)
+
+	mockStore.AssertExpectations(t)
+}
+
+func TestCacheRevokeUser(t *testing.T) {
+	userID := uuid.New()
+	issuedAt := time.Now()
+
+	mockStore := new(MockStore)
+	mockStore.On("IsRevoked", "jti", userID, issuedAt).Return(false, nil).Once()
+	mockStore.On("RevokeUser", userID, mock.Anything).Return(nil).Once()
+	mockStore.On("IsRevoked", "jti", userID, issuedAt).Return(true, nil).Once()
+
+	cache := revocation.NewCache(mockStore, time.Minute)
+
+	revoked, err := cache.IsRevoked(t.Context(), "jti", userID, issuedAt)
+	require.NoError(t, err)
+	require.False(t, revoked)
+
+	require.NoError(t, cache.RevokeUser(t.Context(), userID, time.Now()))
+
+	revoked, err = cache.IsRevoked(t.Context(), "jti", userID, issuedAt)
+	require.NoError(t, err)
+	require.True(t, revoked)
+
+	mockStore.AssertExpectations(t)
+}
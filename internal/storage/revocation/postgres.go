@@ -0,0 +1,64 @@
+package revocation
+
+import (
+	"context"
+	"time"
+
+	"github.com/geekswamp/zen/configs"
+	"github.com/geekswamp/zen/internal/base"
+	"github.com/geekswamp/zen/internal/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
+
+// PostgresStore is a Store backed by the revoked_tokens and user_revocations tables.
+// Revocations made within base.Transactor.WithinTx are part of the transaction.
+type PostgresStore struct {
+	repo base.Repository
+}
+
+// NewPostgres creates a new Store backed by the database of the given repository.
+func NewPostgres(repo base.Repository) Store {
+	return PostgresStore{repo: repo}
+}
+
+func (p PostgresStore) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	return p.repo.DB(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&model.RevokedToken{
+		JTI:         jti,
+		ExpiresTime: expiresAt.UnixMilli(),
+	}).Error
+}
+
+// RevokeUser records the revocation time of the user. The record is only needed until every
+// token issued before it has expired, which is at most one access token lifetime later.
+func (p PostgresStore) RevokeUser(ctx context.Context, userID uuid.UUID, at time.Time) error {
+	return p.repo.DB(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"revoked_time", "expires_time"}),
+	}).Create(&model.UserRevocation{
+		UserID:      userID,
+		RevokedTime: at.UnixMilli(),
+		ExpiresTime: at.Add(configs.Get().JWT.AccessTokenTTL).UnixMilli(),
+	}).Error
+}
+
+// IsRevoked compares the revocation time of the user in whole seconds, the precision of the
+// issue time of a token, so tokens issued in the second of a revocation stay valid.
+func (p PostgresStore) IsRevoked(ctx context.Context, jti string, userID uuid.UUID, issuedAt time.Time) (bool, error) {
+	var count int64
+	if err := p.repo.DB(ctx).Model(&model.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	if count > 0 {
+		return true, nil
+	}
+
+	if err := p.repo.DB(ctx).Model(&model.UserRevocation{}).
+		Where("user_id = ? AND revoked_time / 1000 > ?", userID, issuedAt.Unix()).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
@@ -0,0 +1,21 @@
+package revocation
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Store keeps track of access tokens that must be rejected before they expire.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Revoke rejects the token with the given jti until it expires.
+	Revoke(ctx context.Context, jti string, expiresAt time.Time) error
+
+	// RevokeUser rejects every token of the user issued before the given time.
+	RevokeUser(ctx context.Context, userID uuid.UUID, at time.Time) error
+
+	// IsRevoked reports whether a token identified by its jti, subject and issue time has been revoked.
+	IsRevoked(ctx context.Context, jti string, userID uuid.UUID, issuedAt time.Time) (bool, error)
+}
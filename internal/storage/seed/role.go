@@ -0,0 +1,64 @@
+package seed
+
+import (
+	"github.com/geekswamp/zen/internal/model"
+	"gorm.io/gorm"
+)
+
+type roleSeeder struct{}
+
+var defaultPermissions = []model.Permission{
+	{Name: model.PermissionUserRead, Description: "View any user"},
+	{Name: model.PermissionUserUpdate, Description: "Activate, deactivate and revoke tokens of any user"},
+	{Name: model.PermissionUserDelete, Description: "Delete any user"},
+	{Name: model.PermissionRoleAssign, Description: "Assign roles to users"},
+	{Name: model.PermissionAuditRead, Description: "View the audit log"},
+}
+
+var defaultRoles = map[string][]string{
+	model.RoleAdmin: {
+		model.PermissionUserRead,
+		model.PermissionUserUpdate,
+		model.PermissionUserDelete,
+		model.PermissionRoleAssign,
+		model.PermissionAuditRead,
+	},
+	model.RoleSupport: {
+		model.PermissionUserRead,
+		model.PermissionUserUpdate,
+	},
+}
+
+func init() {
+	RegisterSeeder("roles", roleSeeder{})
+}
+
+// Seed creates the default permissions and roles. Existing rows are kept and missing
+// permissions are added to existing roles, so it is safe to rerun.
+func (s roleSeeder) Seed(db *gorm.DB) error {
+	permissions := map[string]model.Permission{}
+	for _, p := range defaultPermissions {
+		if err := db.Where(model.Permission{Name: p.Name}).Attrs(p).FirstOrCreate(&p).Error; err != nil {
+			return err
+		}
+		permissions[p.Name] = p
+	}
+
+	for name, names := range defaultRoles {
+		role := model.Role{Name: name}
+		if err := db.Where(role).FirstOrCreate(&role).Error; err != nil {
+			return err
+		}
+
+		rolePermissions := make([]model.Permission, 0, len(names))
+		for _, n := range names {
+			rolePermissions = append(rolePermissions, permissions[n])
+		}
+
+		if err := db.Model(&role).Association("Permissions").Append(rolePermissions); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
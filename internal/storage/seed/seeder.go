@@ -1,31 +1,250 @@
 package seed
 
 import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/geekswamp/zen/internal/errors"
 	"github.com/geekswamp/zen/internal/logger"
+	"github.com/geekswamp/zen/pkg/env"
+	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var log = logger.New()
 
+// Seeder inserts data into a schema created by the migrations.
+// Seed runs inside a transaction that also records the run in seed_history.
 type Seeder interface {
-	Migrate(db *gorm.DB) error
-	Seed(db *gorm.DB) error
+	Seed(tx *gorm.DB) error
+}
+
+type Option func(*entry)
+
+// DependsOn makes the seeder run after the named seeders.
+func DependsOn(names ...string) Option {
+	return func(e *entry) {
+		e.dependsOn = append(e.dependsOn, names...)
+	}
+}
+
+// OnlyIn restricts the seeder to the given environments, see env.Dev and env.Pro.
+// Seeders without it run in every environment.
+func OnlyIn(envs ...string) Option {
+	return func(e *entry) {
+		e.envs = append(e.envs, envs...)
+	}
+}
+
+type entry struct {
+	name      string
+	seeder    Seeder
+	dependsOn []string
+	envs      []string
+}
+
+func (e *entry) enabled() bool {
+	return len(e.envs) == 0 || slices.Contains(e.envs, env.Active().Value())
+}
+
+// History is a row of the seed_history table.
+type History struct {
+	Name        string `gorm:"column:name;primaryKey"`
+	Env         string `gorm:"column:env"`
+	RunCount    int    `gorm:"column:run_count"`
+	LastRunTime int64  `gorm:"column:last_run_time"`
+}
+
+func (History) TableName() string {
+	return "seed_history"
+}
+
+// Status describes a registered seeder and its runs.
+type Status struct {
+	Name        string
+	DependsOn   []string
+	Envs        []string
+	Enabled     bool
+	RunCount    int
+	LastRunTime int64
 }
 
-var seeders []Seeder
+var seeders = map[string]*entry{}
 
-func RegisterSeeder(s Seeder) {
-	seeders = append(seeders, s)
+// RegisterSeeder adds a seeder under a unique name. It is meant to be called from init
+// and panics when the name is already taken.
+func RegisterSeeder(name string, s Seeder, opts ...Option) {
+	if _, ok := seeders[name]; ok {
+		panic(fmt.Sprintf("seeder %q is registered twice", name))
+	}
+
+	e := &entry{name: name, seeder: s}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	seeders[name] = e
 }
 
+// RunSeeders runs every seeder of the active environment that has not run yet.
 func RunSeeders(db *gorm.DB) error {
-	for _, s := range seeders {
-		if err := s.Migrate(db); err != nil {
-			return err
+	return Run(db, false)
+}
+
+// Run runs the named seeders, or every seeder of the active environment when none
+// are named, after their dependencies. Seeders that already ran are skipped unless
+// force is set; force never reruns dependencies that were not named.
+func Run(db *gorm.DB, force bool, names ...string) error {
+	plan, err := Plan(names...)
+	if err != nil {
+		return err
+	}
+
+	history, err := loadHistory(db)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range plan {
+		_, ran := history[name]
+		rerun := force && (len(names) == 0 || slices.Contains(names, name))
+		if ran && !rerun {
+			continue
 		}
-		if err := s.Seed(db); err != nil {
-			return err
+
+		if err := run(db, seeders[name]); err != nil {
+			return fmt.Errorf("seeder %s: %w", name, err)
 		}
 	}
+
 	return nil
 }
+
+// Plan returns the named seeders and their dependencies in the order they must run.
+// Without names every seeder of the active environment is planned.
+func Plan(names ...string) ([]string, error) {
+	names = slices.Clone(names)
+	if len(names) == 0 {
+		for name, e := range seeders {
+			if e.enabled() {
+				names = append(names, name)
+			}
+		}
+	}
+	slices.Sort(names)
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+
+	state := map[string]int{}
+	plan := []string{}
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		e, ok := seeders[name]
+		if !ok {
+			return fmt.Errorf("%w: %s", errors.ErrUnknownSeeder, name)
+		}
+
+		if !e.enabled() {
+			return fmt.Errorf("%w: %s", errors.ErrSeederDisabled, name)
+		}
+
+		switch state[name] {
+		case visiting:
+			return fmt.Errorf("%w: %s", errors.ErrSeederCycle, name)
+		case visited:
+			return nil
+		}
+
+		state[name] = visiting
+		for _, dep := range e.dependsOn {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+
+		plan = append(plan, name)
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+
+	return plan, nil
+}
+
+// Statuses lists every registered seeder, sorted by name, with its recorded runs.
+func Statuses(db *gorm.DB) ([]Status, error) {
+	history, err := loadHistory(db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(seeders))
+	for name, e := range seeders {
+		h := history[name]
+		statuses = append(statuses, Status{
+			Name:        name,
+			DependsOn:   e.dependsOn,
+			Envs:        e.envs,
+			Enabled:     e.enabled(),
+			RunCount:    h.RunCount,
+			LastRunTime: h.LastRunTime,
+		})
+	}
+
+	slices.SortFunc(statuses, func(a, b Status) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return statuses, nil
+}
+
+func loadHistory(db *gorm.DB) (map[string]History, error) {
+	rows := []History{}
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	history := make(map[string]History, len(rows))
+	for _, h := range rows {
+		history[h.Name] = h
+	}
+
+	return history, nil
+}
+
+// run seeds and records the run in one transaction, so a failed seeder leaves no partial data.
+func run(db *gorm.DB, e *entry) error {
+	log.Info("Running seeder", zap.String("name", e.name))
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := e.seeder.Seed(tx); err != nil {
+			return err
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "name"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"env":           env.Active().Value(),
+				"run_count":     gorm.Expr("seed_history.run_count + 1"),
+				"last_run_time": time.Now().Local().UnixMilli(),
+			}),
+		}).Create(&History{
+			Name:        e.name,
+			Env:         env.Active().Value(),
+			RunCount:    1,
+			LastRunTime: time.Now().Local().UnixMilli(),
+		}).Error
+	})
+}
@@ -0,0 +1,98 @@
+package seed_test
+
+import (
+	"testing"
+
+	"github.com/geekswamp/zen/internal/errors"
+	"github.com/geekswamp/zen/internal/storage/seed"
+	"github.com/geekswamp/zen/pkg/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type noopSeeder struct{}
+
+func (noopSeeder) Seed(_ *gorm.DB) error {
+	return nil
+}
+
+func TestPlan(t *testing.T) {
+	seed.RegisterSeeder("test-base", noopSeeder{})
+	seed.RegisterSeeder("test-left", noopSeeder{}, seed.DependsOn("test-base"))
+	seed.RegisterSeeder("test-right", noopSeeder{}, seed.DependsOn("test-base"))
+	seed.RegisterSeeder("test-top", noopSeeder{}, seed.DependsOn("test-right", "test-left"))
+	seed.RegisterSeeder("test-pro", noopSeeder{}, seed.OnlyIn(env.Pro))
+	seed.RegisterSeeder("test-needs-pro", noopSeeder{}, seed.DependsOn("test-pro"))
+	seed.RegisterSeeder("test-cycle-a", noopSeeder{}, seed.DependsOn("test-cycle-b"))
+	seed.RegisterSeeder("test-cycle-b", noopSeeder{}, seed.DependsOn("test-cycle-a"))
+	seed.RegisterSeeder("test-missing", noopSeeder{}, seed.DependsOn("test-nowhere"))
+
+	testCases := []struct {
+		name     string
+		names    []string
+		wantPlan []string
+		wantErr  error
+	}{
+		{
+			name:     "Single Seeder",
+			names:    []string{"test-base"},
+			wantPlan: []string{"test-base"},
+		},
+		{
+			name:     "Dependencies First",
+			names:    []string{"test-top"},
+			wantPlan: []string{"test-base", "test-right", "test-left", "test-top"},
+		},
+		{
+			name:     "Shared Dependency Once",
+			names:    []string{"test-right", "test-left"},
+			wantPlan: []string{"test-base", "test-left", "test-right"},
+		},
+		{
+			name:    "Unknown Seeder",
+			names:   []string{"test-nowhere"},
+			wantErr: errors.ErrUnknownSeeder,
+		},
+		{
+			name:    "Unknown Dependency",
+			names:   []string{"test-missing"},
+			wantErr: errors.ErrUnknownSeeder,
+		},
+		{
+			name:    "Other Environment",
+			names:   []string{"test-pro"},
+			wantErr: errors.ErrSeederDisabled,
+		},
+		{
+			name:    "Dependency In Other Environment",
+			names:   []string{"test-needs-pro"},
+			wantErr: errors.ErrSeederDisabled,
+		},
+		{
+			name:    "Cycle",
+			names:   []string{"test-cycle-a"},
+			wantErr: errors.ErrSeederCycle,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			plan, err := seed.Plan(tc.names...)
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantPlan, plan)
+		})
+	}
+}
+
+func TestPlanDefaults(t *testing.T) {
+	plan, err := seed.Plan("demo-users")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"roles", "demo-users"}, plan)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/geekswamp/zen/internal/base"
 	"github.com/geekswamp/zen/internal/model"
+	"github.com/geekswamp/zen/pkg/env"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -12,16 +13,14 @@ import (
 type userSeeder struct{}
 
 func init() {
-	RegisterSeeder(userSeeder{})
-}
-
-func (s userSeeder) Migrate(db *gorm.DB) error {
-	return db.AutoMigrate(&model.User{}, &model.UserPassHash{})
+	RegisterSeeder("demo-users", userSeeder{}, DependsOn("roles"), OnlyIn(env.Dev))
 }
 
+// Seed creates a demo admin. It is skipped when the demo email is already taken,
+// e.g. by databases seeded before seed_history existed.
 func (s userSeeder) Seed(db *gorm.DB) error {
 	var count int64
-	if err := db.Model(&model.User{}).Count(&count).Error; err != nil {
+	if err := db.Model(&model.User{}).Where("email = ?", "john@doe.com").Count(&count).Error; err != nil {
 		return err
 	}
 	if count > 0 {
@@ -56,5 +55,10 @@ func (s userSeeder) Seed(db *gorm.DB) error {
 		return err
 	}
 
-	return nil
+	var admin model.Role
+	if err := db.Where(model.Role{Name: model.RoleAdmin}).First(&admin).Error; err != nil {
+		return err
+	}
+
+	return db.Model(&user).Association("Roles").Append(&admin)
 }
@@ -31,6 +31,10 @@ func init() {
 
 	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
 		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "" {
+			name = strings.SplitN(field.Tag.Get("form"), ",", 2)[0]
+		}
+
 		if name == "-" {
 			return ""
 		}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/geekswamp/zen/internal/core"
+	"github.com/geekswamp/zen/internal/crypto/token"
+	"github.com/geekswamp/zen/internal/http"
+	"github.com/geekswamp/zen/internal/storage/revocation"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	_HeaderAuthorizationKey = "Authorization"
+	_BearerScheme           = "Bearer"
+)
+
+// Auth is a Gin middleware function that authenticates requests using a JWT bearer token.
+// The token is read from the Authorization header and verified with the given provider.
+// Tokens found in the revocation store are rejected even if they have not expired yet.
+// On success the user session is populated from the token claims and stored in the context.
+// Otherwise, it aborts the request with a 401 Unauthorized response.
+func Auth(verifier token.JWTProvider, revoked revocation.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		c := core.NewContext(ctx)
+
+		scheme, tokenStr, found := strings.Cut(ctx.Request.Header.Get(_HeaderAuthorizationKey), " ")
+		tokenStr = strings.TrimSpace(tokenStr)
+		if !found || !strings.EqualFold(scheme, _BearerScheme) || tokenStr == "" {
+			unauthorized(ctx, http.InvalidAuthHeader)
+			return
+		}
+
+		claims, err := verifier.Verify(tokenStr)
+		if err != nil {
+			if errors.Is(err, jwt.ErrTokenExpired) {
+				unauthorized(ctx, http.TokenExpired)
+				return
+			}
+
+			unauthorized(ctx, http.InvalidToken)
+			return
+		}
+
+		ID, err := uuid.Parse(claims.Subject)
+		if err != nil || claims.IssuedAt == nil {
+			unauthorized(ctx, http.InvalidToken)
+			return
+		}
+
+		isRevoked, err := revoked.IsRevoked(ctx.Request.Context(), claims.ID, ID, claims.IssuedAt.Time)
+		if err != nil {
+			http.New().Error(ctx, err)
+			ctx.Abort()
+			return
+		}
+
+		if isRevoked {
+			unauthorized(ctx, http.TokenRevoked)
+			return
+		}
+
+		session := core.UserSession{
+			ID:          ID,
+			SessionID:   claims.SessionID,
+			TokenID:     claims.ID,
+			Roles:       claims.Roles,
+			Permissions: claims.Permissions,
+			Extra:       claims.Extra,
+		}
+
+		if claims.ExpiresAt != nil {
+			session.ExpiresAt = claims.ExpiresAt.Time
+		}
+
+		c.SetUserSession(session)
+		ctx.Next()
+	}
+}
+
+func unauthorized(ctx *gin.Context, code *http.ErrorCode) {
+	http.New().Unauthorized(ctx, http.Error{Code: code.Code(), Reason: code.Detail()})
+	ctx.Abort()
+}
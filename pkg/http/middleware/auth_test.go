@@ -0,0 +1,151 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/geekswamp/zen/internal/core"
+	"github.com/geekswamp/zen/internal/crypto/token"
+	"github.com/geekswamp/zen/pkg/http/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockJWTProvider struct {
+	mock.Mock
+}
+
+func (m *MockJWTProvider) Generate() (hash string, err error) {
+	args := m.Called()
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockJWTProvider) Verify(tokenStr string) (claims *token.Claims, err error) {
+	args := m.Called(tokenStr)
+	if claims, ok := args.Get(0).(*token.Claims); ok {
+		return claims, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type MockRevocationStore struct {
+	mock.Mock
+}
+
+func (m *MockRevocationStore) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	args := m.Called(jti, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockRevocationStore) RevokeUser(ctx context.Context, userID uuid.UUID, at time.Time) error {
+	args := m.Called(userID, at)
+	return args.Error(0)
+}
+
+func (m *MockRevocationStore) IsRevoked(ctx context.Context, jti string, userID uuid.UUID, issuedAt time.Time) (bool, error) {
+	args := m.Called(jti, userID, issuedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	issuedAt := jwt.NewNumericDate(time.Now())
+
+	mockJWT := new(MockJWTProvider)
+	mockJWT.On("Verify", "valid.jwt.token").Return(&token.Claims{RegisteredClaims: jwt.RegisteredClaims{ID: "valid", Subject: userID.String(), IssuedAt: issuedAt}}, nil)
+	mockJWT.On("Verify", "revoked.jwt.token").Return(&token.Claims{RegisteredClaims: jwt.RegisteredClaims{ID: "revoked", Subject: userID.String(), IssuedAt: issuedAt}}, nil)
+	mockJWT.On("Verify", "expired.jwt.token").Return(nil, jwt.ErrTokenExpired)
+	mockJWT.On("Verify", "invalid.jwt.token").Return(nil, errors.New("invalid token"))
+	mockJWT.On("Verify", "bad-subject.jwt.token").Return(&token.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "not-a-uuid", IssuedAt: issuedAt}}, nil)
+
+	mockStore := new(MockRevocationStore)
+	mockStore.On("IsRevoked", "valid", userID, issuedAt.Time).Return(false, nil)
+	mockStore.On("IsRevoked", "revoked", userID, issuedAt.Time).Return(true, nil)
+
+	engine := gin.New()
+	engine.Use(middleware.RequestID(), middleware.Auth(mockJWT, mockStore))
+	engine.GET("/", func(ctx *gin.Context) {
+		c := core.NewContext(ctx)
+		ctx.String(http.StatusOK, c.GetUserSession().ID.String())
+	})
+
+	testCases := []struct {
+		name     string
+		header   string
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "Valid Token",
+			header:   "Bearer valid.jwt.token",
+			wantCode: http.StatusOK,
+			wantBody: userID.String(),
+		},
+		{
+			name:     "Lowercase Scheme",
+			header:   "bearer valid.jwt.token",
+			wantCode: http.StatusOK,
+			wantBody: userID.String(),
+		},
+		{
+			name:     "Missing Header",
+			header:   "",
+			wantCode: http.StatusUnauthorized,
+			wantBody: "ERR-HR40002",
+		},
+		{
+			name:     "Wrong Scheme",
+			header:   "Basic dXNlcjpwYXNz",
+			wantCode: http.StatusUnauthorized,
+			wantBody: "ERR-HR40002",
+		},
+		{
+			name:     "Expired Token",
+			header:   "Bearer expired.jwt.token",
+			wantCode: http.StatusUnauthorized,
+			wantBody: "ERR-AU40103",
+		},
+		{
+			name:     "Invalid Token",
+			header:   "Bearer invalid.jwt.token",
+			wantCode: http.StatusUnauthorized,
+			wantBody: "ERR-AU40102",
+		},
+		{
+			name:     "Revoked Token",
+			header:   "Bearer revoked.jwt.token",
+			wantCode: http.StatusUnauthorized,
+			wantBody: "ERR-AU40107",
+		},
+		{
+			name:     "Invalid Subject",
+			header:   "Bearer bad-subject.jwt.token",
+			wantCode: http.StatusUnauthorized,
+			wantBody: "ERR-AU40102",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.wantCode, w.Code)
+			assert.Contains(t, w.Body.String(), tc.wantBody)
+		})
+	}
+}
@@ -29,7 +29,8 @@ func DefaultConfig() Config {
 	return Config{
 		AllowAllOrigins:  true,
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS", "HEAD"},
-		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type"},
+		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization", "If-Match", "X-Request-ID"},
+		ExposeHeaders:    []string{"ETag", "X-Request-ID"},
 		AllowCredentials: false,
 		MaxAge:           12 * time.Hour,
 	}
@@ -0,0 +1,50 @@
+package cors_test
+
+import (
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/geekswamp/zen/pkg/http/middleware/cors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultPreflight(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		name    string
+		method  string
+		headers []string
+	}{
+		{name: "Bearer Token", method: nethttp.MethodGet, headers: []string{"Authorization"}},
+		{name: "Conditional Update", method: nethttp.MethodPatch, headers: []string{"Authorization", "Content-Type", "If-Match"}},
+		{name: "Request ID", method: nethttp.MethodPost, headers: []string{"X-Request-ID"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			engine := gin.New()
+			engine.Use(cors.Default())
+			engine.Any("/", func(ctx *gin.Context) { ctx.Status(nethttp.StatusOK) })
+
+			req := httptest.NewRequest(nethttp.MethodOptions, "/", nil)
+			req.Header.Set("Origin", "https://app.example.com")
+			req.Header.Set("Access-Control-Request-Method", tc.method)
+			req.Header.Set("Access-Control-Request-Headers", strings.Join(tc.headers, ", "))
+
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, nethttp.StatusNoContent, w.Code)
+			assert.Contains(t, w.Header().Get("Access-Control-Allow-Methods"), tc.method)
+
+			allowed := strings.Split(strings.ToLower(w.Header().Get("Access-Control-Allow-Headers")), ",")
+			for _, header := range tc.headers {
+				assert.Contains(t, allowed, strings.ToLower(header))
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"github.com/geekswamp/zen/internal/core"
+	"github.com/geekswamp/zen/internal/http"
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission is a Gin middleware function that only lets requests through when
+// the user session holds all the given permissions. It must be registered after Auth.
+// Requests without a session are aborted with 401 Unauthorized, requests lacking a
+// permission with 403 Forbidden.
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		c := core.NewContext(ctx)
+
+		session := c.GetUserSession()
+		if session == nil {
+			unauthorized(ctx, http.InvalidAuthHeader)
+			return
+		}
+
+		for _, permission := range permissions {
+			if !session.HasPermission(permission) {
+				http.New().Forbidden(ctx)
+				ctx.Abort()
+				return
+			}
+		}
+
+		ctx.Next()
+	}
+}
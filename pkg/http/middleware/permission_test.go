@@ -0,0 +1,65 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/geekswamp/zen/internal/core"
+	"github.com/geekswamp/zen/pkg/http/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequirePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		name     string
+		session  *core.UserSession
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "Granted",
+			session:  &core.UserSession{ID: uuid.New(), Permissions: []string{"user:read", "user:delete"}},
+			wantCode: http.StatusOK,
+			wantBody: "ok",
+		},
+		{
+			name:     "Missing Permission",
+			session:  &core.UserSession{ID: uuid.New(), Permissions: []string{"user:read"}},
+			wantCode: http.StatusForbidden,
+			wantBody: "ERR-PA40003",
+		},
+		{
+			name:     "No Session",
+			session:  nil,
+			wantCode: http.StatusUnauthorized,
+			wantBody: "ERR-HR40002",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			engine := gin.New()
+			engine.Use(middleware.RequestID(), func(ctx *gin.Context) {
+				if tc.session != nil {
+					c := core.NewContext(ctx)
+					c.SetUserSession(*tc.session)
+				}
+				ctx.Next()
+			})
+			engine.GET("/", middleware.RequirePermission("user:delete"), func(ctx *gin.Context) {
+				ctx.String(http.StatusOK, "ok")
+			})
+
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, tc.wantCode, w.Code)
+			assert.Contains(t, w.Body.String(), tc.wantBody)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package middleware_test
+
+import (
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/geekswamp/zen/internal/http"
+	"github.com/geekswamp/zen/pkg/http/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		name     string
+		timeout  time.Duration
+		wantCode int
+		wantBody string
+	}{
+		{name: "Completed In Time", timeout: time.Second, wantCode: nethttp.StatusOK, wantBody: "ok"},
+		{name: "Timed Out", timeout: time.Millisecond, wantCode: nethttp.StatusServiceUnavailable, wantBody: "ERR-SY50301"},
+		{name: "Disabled", timeout: 0, wantCode: nethttp.StatusOK, wantBody: "ok"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			engine := gin.New()
+			engine.Use(middleware.RequestID(), middleware.Timeout(tc.timeout))
+			engine.GET("/", func(ctx *gin.Context) {
+				select {
+				case <-ctx.Request.Context().Done():
+					http.New().Error(ctx, ctx.Request.Context().Err())
+				case <-time.After(50 * time.Millisecond):
+					ctx.String(nethttp.StatusOK, "ok")
+				}
+			})
+
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, httptest.NewRequest(nethttp.MethodGet, "/", nil))
+
+			assert.Equal(t, tc.wantCode, w.Code)
+			assert.Contains(t, w.Body.String(), tc.wantBody)
+		})
+	}
+}